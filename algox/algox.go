@@ -1 +1,2 @@
+// Package algox groups generic algorithms.
 package algox
@@ -0,0 +1,221 @@
+// Package diffx computes Myers diffs of sequences and applies them as patches.
+package diffx
+
+import "errors"
+
+// Op is the kind of a Hunk.
+type Op int8
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+func (op Op) String() string {
+	switch op {
+	case Equal:
+		return "equal"
+	case Delete:
+		return "delete"
+	case Insert:
+		return "insert"
+	}
+	return "unknown"
+}
+
+// Hunk is a run of items that are equal in both sequences, deleted from the
+// first one or inserted into the second one.
+type Hunk[T any] struct {
+	Op    Op
+	Items []T
+}
+
+var ErrPatchMismatch = errors.New("patch does not match the sequence")
+
+// Diff returns a shortest edit script that turns a into b.
+func Diff[T comparable](a, b []T) []Hunk[T] {
+	return DiffCustom(a, b, func(x, y T) bool {
+		return x == y
+	})
+}
+
+// DiffCustom is Diff with a custom equality function. It uses Myers' O(ND)
+// algorithm, where D is the number of edits, after trimming the common prefix
+// and suffix. Equal hunks hold the items of a.
+func DiffCustom[T any](a, b []T, equal func(x, y T) bool) []Hunk[T] {
+	var script []Hunk[T]
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && equal(a[prefix], b[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && equal(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+
+	script = appendHunk(script, Equal, a[:prefix]...)
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	// within a change, deletes are emitted before inserts
+	var x, y, dx, iy int
+	for _, op := range myers(ma, mb, equal) {
+		switch op {
+		case Equal:
+			script = appendHunk(script, Delete, ma[dx:x]...)
+			script = appendHunk(script, Insert, mb[iy:y]...)
+			script = appendHunk(script, Equal, ma[x])
+			x++
+			y++
+			dx, iy = x, y
+		case Delete:
+			x++
+		case Insert:
+			y++
+		}
+	}
+	script = appendHunk(script, Delete, ma[dx:x]...)
+	script = appendHunk(script, Insert, mb[iy:y]...)
+	script = appendHunk(script, Equal, a[len(a)-suffix:]...)
+	return script
+}
+
+// Apply turns a into b with a script made by Diff(a, b).
+func Apply[T comparable](a []T, script []Hunk[T]) ([]T, error) {
+	return ApplyCustom(a, script, func(x, y T) bool {
+		return x == y
+	})
+}
+
+// ApplyCustom is Apply with a custom equality function. It returns
+// ErrPatchMismatch if equal or deleted items of the script are not found in a.
+func ApplyCustom[T any](a []T, script []Hunk[T], equal func(x, y T) bool) ([]T, error) {
+	var out []T
+	var x int
+	for _, h := range script {
+		switch h.Op {
+		case Equal, Delete:
+			if len(a)-x < len(h.Items) {
+				return nil, ErrPatchMismatch
+			}
+			for i, v := range h.Items {
+				if !equal(a[x+i], v) {
+					return nil, ErrPatchMismatch
+				}
+			}
+			if h.Op == Equal {
+				out = append(out, a[x:x+len(h.Items)]...)
+			}
+			x += len(h.Items)
+		case Insert:
+			out = append(out, h.Items...)
+		}
+	}
+	if x != len(a) {
+		return nil, ErrPatchMismatch
+	}
+	return out, nil
+}
+
+// Reverse returns the script that undoes script: applying it to b gives a.
+func Reverse[T any](script []Hunk[T]) []Hunk[T] {
+	rev := make([]Hunk[T], len(script))
+	for i, h := range script {
+		switch h.Op {
+		case Delete:
+			h.Op = Insert
+		case Insert:
+			h.Op = Delete
+		}
+		rev[i] = h
+	}
+	// a delete must precede an insert at the same position, like Diff emits
+	for i := 1; i < len(rev); i++ {
+		if rev[i-1].Op == Insert && rev[i].Op == Delete {
+			rev[i-1], rev[i] = rev[i], rev[i-1]
+		}
+	}
+	return rev
+}
+
+func appendHunk[T any](script []Hunk[T], op Op, items ...T) []Hunk[T] {
+	if len(items) == 0 {
+		return script
+	}
+	if n := len(script); n > 0 && script[n-1].Op == op {
+		script[n-1].Items = append(script[n-1].Items, items...)
+		return script
+	}
+	return append(script, Hunk[T]{Op: op, Items: append([]T(nil), items...)})
+}
+
+// myers returns the per-item operations of a shortest edit script.
+func myers[T any](a, b []T, equal func(x, y T) bool) []Op {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	off := max
+	v := make([]int, 2*max+2)
+	// trace[d] holds v[-d..d] as it was before step d
+	var trace [][]int
+	var x, y int
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y = x - k
+			for x < n && y < m && equal(a[x], b[y]) {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	ops := make([]Op, 0, max)
+	for d := len(trace) - 1; d >= 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return prev[k+d] }
+		k := x - y
+		var pk int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := 0
+		if d > 0 {
+			px = at(pk)
+		}
+		py := px - pk
+		for x > px && y > py {
+			ops = append(ops, Equal)
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == px {
+				ops = append(ops, Insert)
+			} else {
+				ops = append(ops, Delete)
+			}
+		}
+		x, y = px, py
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
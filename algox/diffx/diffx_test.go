@@ -0,0 +1,132 @@
+package diffx_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/diffx"
+	"golang.org/x/exp/slices"
+)
+
+func editDistance(script []diffx.Hunk[byte]) int {
+	var d int
+	for _, h := range script {
+		if h.Op != diffx.Equal {
+			d += len(h.Items)
+		}
+	}
+	return d
+}
+
+// lcs is the textbook O(NM) longest common subsequence length.
+func lcs(a, b []byte) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else if dp[i-1][j] > dp[i][j-1] {
+				dp[i][j] = dp[i-1][j]
+			} else {
+				dp[i][j] = dp[i][j-1]
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
+
+func TestDiff(t *testing.T) {
+	script := diffx.Diff([]byte("ABCABBA"), []byte("CBABAC"))
+	if d := editDistance(script); d != 5 {
+		t.Fatalf("edit distance = %d, want 5", d)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		a, b := make([]byte, r.Intn(30)), make([]byte, r.Intn(30))
+		for i := range a {
+			a[i] = "abc"[r.Intn(3)]
+		}
+		for i := range b {
+			b[i] = "abc"[r.Intn(3)]
+		}
+
+		script := diffx.Diff(a, b)
+		if d, want := editDistance(script), len(a)+len(b)-2*lcs(a, b); d != want {
+			t.Fatalf("Diff(%q, %q) has %d edits, want %d", a, b, d, want)
+		}
+
+		got, err := diffx.Apply(a, script)
+		if err != nil || string(got) != string(b) {
+			t.Fatalf("Apply(%q) = %q, %v, want %q", a, got, err, b)
+		}
+		got, err = diffx.Apply(b, diffx.Reverse(script))
+		if err != nil || string(got) != string(a) {
+			t.Fatalf("Apply(Reverse) = %q, %v, want %q", got, err, a)
+		}
+	}
+}
+
+func TestApplyMismatch(t *testing.T) {
+	script := diffx.Diff([]int{1, 2, 3}, []int{1, 3})
+	if _, err := diffx.Apply([]int{1, 5, 3}, script); err != diffx.ErrPatchMismatch {
+		t.Fatalf("Apply error = %v", err)
+	}
+	if _, err := diffx.Apply([]int{1, 2, 3, 4}, script); err != diffx.ErrPatchMismatch {
+		t.Fatalf("Apply error = %v", err)
+	}
+}
+
+func TestDiffCustom(t *testing.T) {
+	script := diffx.DiffCustom([]string{"A", "b"}, []string{"a", "B", "c"}, strings.EqualFold)
+	want := []diffx.Hunk[string]{
+		{Op: diffx.Equal, Items: []string{"A", "b"}},
+		{Op: diffx.Insert, Items: []string{"c"}},
+	}
+	if len(script) != len(want) {
+		t.Fatalf("DiffCustom = %v", script)
+	}
+	for i := range want {
+		if script[i].Op != want[i].Op || !slices.Equal(script[i].Items, want[i].Items) {
+			t.Fatalf("DiffCustom = %v", script)
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := strings.Split("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm", "\n")
+	b := strings.Split("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn", "\n")
+
+	got := diffx.Unified("old", "new", a, b, diffx.DefaultContext)
+	want := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`
+	if got != want {
+		t.Fatalf("Unified =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := diffx.Unified("old", "new", a, a, 3); got != "" {
+		t.Fatalf("Unified of equal input = %q", got)
+	}
+
+	got = diffx.Unified("old", "new", nil, []string{"x"}, 3)
+	if want := "--- old\n+++ new\n@@ -0,0 +1 @@\n+x\n"; got != want {
+		t.Fatalf("Unified from empty =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+package diffx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of context lines used by diff -u.
+const DefaultContext = 3
+
+// Unified renders the difference between the lines of a and b in unified
+// diff format, with context unchanged lines around each change. Lines must
+// not contain their trailing newline. It returns "" if a and b are equal.
+func Unified(aName, bName string, a, b []string, context int) string {
+	return UnifiedScript(aName, bName, Diff(a, b), context)
+}
+
+// UnifiedScript is Unified for a script made by Diff or DiffCustom.
+func UnifiedScript(aName, bName string, script []Hunk[string], context int) string {
+	if context < 0 {
+		context = 0
+	}
+
+	type line struct {
+		op     Op
+		text   string
+		ai, bi int
+	}
+	var lines []line
+	var ai, bi int
+	changed := false
+	for _, h := range script {
+		for _, text := range h.Items {
+			lines = append(lines, line{h.Op, text, ai, bi})
+			switch h.Op {
+			case Equal:
+				ai++
+				bi++
+			case Delete:
+				ai++
+				changed = true
+			case Insert:
+				bi++
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+
+	i := 0
+	for {
+		for i < len(lines) && lines[i].op == Equal {
+			i++
+		}
+		if i == len(lines) {
+			break
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for {
+			for end < len(lines) && lines[end].op != Equal {
+				end++
+			}
+			next := end
+			for next < len(lines) && lines[next].op == Equal {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		stop := end + context
+		if stop > len(lines) {
+			stop = len(lines)
+		}
+
+		var na, nb int
+		for _, l := range lines[start:stop] {
+			if l.op != Insert {
+				na++
+			}
+			if l.op != Delete {
+				nb++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", unifiedRange(lines[start].ai, na), unifiedRange(lines[start].bi, nb))
+		for _, l := range lines[start:stop] {
+			switch l.op {
+			case Equal:
+				sb.WriteByte(' ')
+			case Delete:
+				sb.WriteByte('-')
+			case Insert:
+				sb.WriteByte('+')
+			}
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// unifiedRange formats a 0-based start and a count as a 1-based range. Empty
+// ranges name the line before them, like diff -u does.
+func unifiedRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
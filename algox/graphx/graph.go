@@ -0,0 +1,112 @@
+// Package graphx implements a generic graph with traversals, topological
+// sorting, strongly connected components, shortest paths and spanning trees.
+package graphx
+
+// Edge is a weighted edge from From to To.
+type Edge[V comparable, W any] struct {
+	From   V
+	To     V
+	Weight W
+}
+
+// Graph is an adjacency-list graph with vertices of type V and edge weights of
+// type W. Vertices and edges are iterated in insertion order, so every
+// algorithm in this package is deterministic.
+//
+// Graph is not safe for concurrent modification.
+type Graph[V comparable, W any] struct {
+	directed bool
+	index    map[V]int
+	vertices []V
+	adj      [][]Edge[V, W]
+}
+
+func NewDirected[V comparable, W any]() *Graph[V, W] {
+	return &Graph[V, W]{directed: true, index: make(map[V]int)}
+}
+
+// NewUndirected returns a graph where AddEdge connects both endpoints.
+func NewUndirected[V comparable, W any]() *Graph[V, W] {
+	return &Graph[V, W]{directed: false, index: make(map[V]int)}
+}
+
+func (g *Graph[V, W]) Directed() bool {
+	return g.directed
+}
+
+// AddVertex adds v if it is not in the graph yet.
+func (g *Graph[V, W]) AddVertex(v V) {
+	g.vertex(v)
+}
+
+// AddEdge adds an edge, adding missing endpoints. Parallel edges are allowed.
+func (g *Graph[V, W]) AddEdge(from, to V, weight W) {
+	f, t := g.vertex(from), g.vertex(to)
+	g.adj[f] = append(g.adj[f], Edge[V, W]{From: from, To: to, Weight: weight})
+	if !g.directed && f != t {
+		g.adj[t] = append(g.adj[t], Edge[V, W]{From: to, To: from, Weight: weight})
+	}
+}
+
+func (g *Graph[V, W]) HasVertex(v V) bool {
+	_, ok := g.index[v]
+	return ok
+}
+
+func (g *Graph[V, W]) HasEdge(from, to V) bool {
+	f, ok := g.index[from]
+	if !ok {
+		return false
+	}
+	for _, e := range g.adj[f] {
+		if e.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Vertices returns every vertex in insertion order.
+func (g *Graph[V, W]) Vertices() []V {
+	return append([]V(nil), g.vertices...)
+}
+
+// Neighbors returns the outgoing edges of v.
+func (g *Graph[V, W]) Neighbors(v V) []Edge[V, W] {
+	i, ok := g.index[v]
+	if !ok {
+		return nil
+	}
+	return append([]Edge[V, W](nil), g.adj[i]...)
+}
+
+// Edges returns every edge once. Undirected edges are reported from the
+// endpoint that was added to the graph first.
+func (g *Graph[V, W]) Edges() []Edge[V, W] {
+	var edges []Edge[V, W]
+	for i, adj := range g.adj {
+		for _, e := range adj {
+			if !g.directed && g.index[e.To] < i {
+				continue
+			}
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// Len returns the number of vertices.
+func (g *Graph[V, W]) Len() int {
+	return len(g.vertices)
+}
+
+func (g *Graph[V, W]) vertex(v V) int {
+	if i, ok := g.index[v]; ok {
+		return i
+	}
+	i := len(g.vertices)
+	g.index[v] = i
+	g.vertices = append(g.vertices, v)
+	g.adj = append(g.adj, nil)
+	return i
+}
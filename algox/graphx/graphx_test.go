@@ -0,0 +1,172 @@
+package graphx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/graphx"
+	"golang.org/x/exp/slices"
+)
+
+func TestTraverse(t *testing.T) {
+	g := graphx.NewDirected[string, int]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 1)
+	g.AddEdge("b", "d", 1)
+	g.AddEdge("c", "d", 1)
+	g.AddEdge("d", "e", 1)
+	g.AddVertex("z")
+
+	var bfs []string
+	var depths []int
+	graphx.BFS(g, "a", func(v string, depth int) bool {
+		bfs = append(bfs, v)
+		depths = append(depths, depth)
+		return true
+	})
+	if !slices.Equal(bfs, []string{"a", "b", "c", "d", "e"}) || !slices.Equal(depths, []int{0, 1, 1, 2, 3}) {
+		t.Fatalf("BFS = %v %v", bfs, depths)
+	}
+
+	var dfs []string
+	graphx.DFS(g, "a", func(v string) bool {
+		dfs = append(dfs, v)
+		return v != "e"
+	})
+	if !slices.Equal(dfs, []string{"a", "b", "d", "e"}) {
+		t.Fatalf("DFS = %v", dfs)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := graphx.NewDirected[string, struct{}]()
+	g.AddEdge("shirt", "tie", struct{}{})
+	g.AddEdge("tie", "jacket", struct{}{})
+	g.AddEdge("pants", "shoes", struct{}{})
+	g.AddEdge("pants", "belt", struct{}{})
+	g.AddEdge("belt", "jacket", struct{}{})
+	g.AddEdge("shirt", "belt", struct{}{})
+
+	order, err := graphx.TopologicalSort(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos := make(map[string]int)
+	for i, v := range order {
+		pos[v] = i
+	}
+	for _, e := range g.Edges() {
+		if pos[e.From] > pos[e.To] {
+			t.Fatalf("TopologicalSort = %v breaks edge %v", order, e)
+		}
+	}
+
+	g.AddEdge("jacket", "x", struct{}{})
+	g.AddEdge("x", "shirt", struct{}{})
+	_, err = graphx.TopologicalSort(g)
+	var cerr *graphx.CycleError[string]
+	if !errors.Is(err, graphx.ErrCycle) || !errors.As(err, &cerr) {
+		t.Fatalf("TopologicalSort error = %v", err)
+	}
+	cycle := cerr.Cycle
+	for i, v := range cycle {
+		if !g.HasEdge(v, cycle[(i+1)%len(cycle)]) {
+			t.Fatalf("reported cycle %v is not a cycle", cycle)
+		}
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := graphx.NewDirected[int, int]()
+	for _, e := range [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {4, 5}, {5, 4}, {6, 5}} {
+		g.AddEdge(e[0], e[1], 0)
+	}
+	sccs := graphx.StronglyConnectedComponents(g)
+	if len(sccs) != 3 {
+		t.Fatalf("StronglyConnectedComponents = %v", sccs)
+	}
+	for _, c := range sccs {
+		slices.Sort(c)
+	}
+	if !slices.Equal(sccs[0], []int{4, 5}) || !slices.Equal(sccs[1], []int{1, 2, 3}) || !slices.Equal(sccs[2], []int{6}) {
+		t.Fatalf("StronglyConnectedComponents = %v", sccs)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	g := graphx.NewDirected[string, float64]()
+	g.AddEdge("s", "a", 1)
+	g.AddEdge("s", "b", 4)
+	g.AddEdge("a", "b", 2)
+	g.AddEdge("a", "c", 6)
+	g.AddEdge("b", "c", 3)
+	g.AddVertex("unreachable")
+
+	path, dist, ok := graphx.ShortestPath(g, "s", "c")
+	if !ok || dist != 6 || !slices.Equal(path, []string{"s", "a", "b", "c"}) {
+		t.Fatalf("ShortestPath = %v, %v, %v", path, dist, ok)
+	}
+	if _, _, ok := graphx.ShortestPath(g, "s", "unreachable"); ok {
+		t.Fatal("ShortestPath found an unreachable vertex")
+	}
+	if path, dist, ok := graphx.ShortestPath(g, "s", "s"); !ok || dist != 0 || !slices.Equal(path, []string{"s"}) {
+		t.Fatalf("ShortestPath(s, s) = %v, %v, %v", path, dist, ok)
+	}
+
+	dist2, prev := graphx.Dijkstra(g, "s")
+	if len(dist2) != 4 || dist2["b"] != 3 || prev["c"] != "b" {
+		t.Fatalf("Dijkstra = %v, %v", dist2, prev)
+	}
+}
+
+func TestAStarGrid(t *testing.T) {
+	type point struct{ x, y int }
+	g := graphx.NewUndirected[point, int]()
+	wall := map[point]bool{{2, 0}: true, {2, 1}: true, {2, 2}: true, {2, 3}: true}
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			p := point{x, y}
+			if wall[p] {
+				continue
+			}
+			if q := (point{x + 1, y}); x+1 < 5 && !wall[q] {
+				g.AddEdge(p, q, 1)
+			}
+			if q := (point{x, y + 1}); y+1 < 5 && !wall[q] {
+				g.AddEdge(p, q, 1)
+			}
+		}
+	}
+
+	dst := point{4, 0}
+	manhattan := func(p point) int {
+		dx, dy := dst.x-p.x, dst.y-p.y
+		if dx < 0 {
+			dx = -dx
+		}
+		if dy < 0 {
+			dy = -dy
+		}
+		return dx + dy
+	}
+	path, dist, ok := graphx.AStar(g, point{0, 0}, dst, manhattan)
+	if !ok || dist != 12 || len(path) != 13 {
+		t.Fatalf("AStar = %v, %v, %v", path, dist, ok)
+	}
+}
+
+func TestMinimumSpanningTree(t *testing.T) {
+	g := graphx.NewUndirected[string, int]()
+	g.AddEdge("a", "b", 4)
+	g.AddEdge("a", "c", 1)
+	g.AddEdge("b", "c", 2)
+	g.AddEdge("b", "d", 5)
+	g.AddEdge("c", "d", 8)
+	g.AddEdge("d", "e", 3)
+	g.AddEdge("x", "y", 7)
+
+	tree, total := graphx.MinimumSpanningTree(g)
+	if total != 18 || len(tree) != 5 {
+		t.Fatalf("MinimumSpanningTree = %v, %d", tree, total)
+	}
+}
@@ -0,0 +1,41 @@
+package graphx
+
+import "github.com/unsafe-risk/utilx/algox/sortx"
+
+// MinimumSpanningTree returns the edges of a minimum spanning forest using
+// Kruskal's algorithm, treating every edge as undirected. Edges of equal
+// weight are taken in insertion order. The total weight is returned too.
+func MinimumSpanningTree[V comparable, W Weight](g *Graph[V, W]) ([]Edge[V, W], W) {
+	edges := g.Edges()
+	sortx.SortStableCustom(edges, func(i, j Edge[V, W]) bool {
+		return i.Weight < j.Weight
+	})
+
+	parent := make([]int, g.Len())
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(v int) int {
+		for parent[v] != v {
+			parent[v] = parent[parent[v]]
+			v = parent[v]
+		}
+		return v
+	}
+
+	var tree []Edge[V, W]
+	var total W
+	for _, e := range edges {
+		a, b := find(g.index[e.From]), find(g.index[e.To])
+		if a == b {
+			continue
+		}
+		parent[a] = b
+		tree = append(tree, e)
+		total += e.Weight
+		if len(tree) == g.Len()-1 {
+			break
+		}
+	}
+	return tree, total
+}
@@ -0,0 +1,130 @@
+package graphx
+
+import (
+	"container/heap"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Weight is the constraint for edge weights of the shortest path and
+// spanning tree algorithms. Weights must not be negative.
+type Weight interface {
+	constraints.Integer | constraints.Float
+}
+
+// Dijkstra computes the shortest distance from src to every reachable vertex.
+// prev maps each reachable vertex except src to its predecessor on a shortest
+// path.
+func Dijkstra[V comparable, W Weight](g *Graph[V, W], src V) (dist map[V]W, prev map[V]V) {
+	s, ok := g.index[src]
+	if !ok {
+		return nil, nil
+	}
+	d, p := search(g, s, -1, nil)
+
+	dist = make(map[V]W)
+	prev = make(map[V]V)
+	for v := range g.vertices {
+		if v == s || p[v] >= 0 {
+			dist[g.vertices[v]] = d[v]
+		}
+		if p[v] >= 0 {
+			prev[g.vertices[v]] = g.vertices[p[v]]
+		}
+	}
+	return dist, prev
+}
+
+// ShortestPath returns a shortest path from src to dst and its length.
+// ok is false if dst is not reachable.
+func ShortestPath[V comparable, W Weight](g *Graph[V, W], src, dst V) (path []V, dist W, ok bool) {
+	return AStar(g, src, dst, nil)
+}
+
+// AStar is ShortestPath guided by heuristic, an estimate of the distance from
+// a vertex to dst. The heuristic must never overestimate, or the path found
+// may not be the shortest. A nil heuristic makes it Dijkstra's algorithm.
+func AStar[V comparable, W Weight](g *Graph[V, W], src, dst V, heuristic func(v V) W) (path []V, dist W, ok bool) {
+	s, ok1 := g.index[src]
+	t, ok2 := g.index[dst]
+	if !ok1 || !ok2 {
+		return nil, 0, false
+	}
+
+	var h func(v int) W
+	if heuristic != nil {
+		h = func(v int) W { return heuristic(g.vertices[v]) }
+	}
+	d, p := search(g, s, t, h)
+	if t != s && p[t] < 0 {
+		return nil, 0, false
+	}
+
+	for v := t; v != s; v = p[v] {
+		path = append(path, g.vertices[v])
+	}
+	path = append(path, src)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, d[t], true
+}
+
+// search runs A* from s, stopping early once t is settled (t < 0 for none).
+// p[v] is -1 for unreached vertices and for s.
+func search[V comparable, W Weight](g *Graph[V, W], s, t int, h func(v int) W) (d []W, p []int) {
+	d = make([]W, g.Len())
+	p = make([]int, g.Len())
+	for i := range p {
+		p[i] = -1
+	}
+	done := make([]bool, g.Len())
+
+	pq := &pathQueue[W]{}
+	heap.Push(pq, pathItem[W]{v: s})
+	for pq.Len() > 0 {
+		it := heap.Pop(pq).(pathItem[W])
+		if done[it.v] {
+			continue
+		}
+		done[it.v] = true
+		if it.v == t {
+			break
+		}
+		for _, e := range g.adj[it.v] {
+			w := g.index[e.To]
+			if done[w] || w == s {
+				continue
+			}
+			nd := d[it.v] + e.Weight
+			if p[w] >= 0 && nd >= d[w] {
+				continue
+			}
+			d[w], p[w] = nd, it.v
+			prio := nd
+			if h != nil {
+				prio += h(w)
+			}
+			heap.Push(pq, pathItem[W]{v: w, prio: prio})
+		}
+	}
+	return d, p
+}
+
+type pathItem[W Weight] struct {
+	v    int
+	prio W
+}
+
+type pathQueue[W Weight] []pathItem[W]
+
+func (q pathQueue[W]) Len() int           { return len(q) }
+func (q pathQueue[W]) Less(i, j int) bool { return q[i].prio < q[j].prio }
+func (q pathQueue[W]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue[W]) Push(x any)        { *q = append(*q, x.(pathItem[W])) }
+func (q *pathQueue[W]) Pop() any {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
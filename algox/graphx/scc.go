@@ -0,0 +1,64 @@
+package graphx
+
+// StronglyConnectedComponents returns the strongly connected components of a
+// directed graph using Tarjan's algorithm. Components are returned in reverse
+// topological order: no edge leads from a component to a later one.
+func StronglyConnectedComponents[V comparable, W any](g *Graph[V, W]) [][]V {
+	t := tarjan[V, W]{
+		g:       g,
+		index:   make([]int, g.Len()),
+		low:     make([]int, g.Len()),
+		onStack: make([]bool, g.Len()),
+	}
+	for v := range g.vertices {
+		if t.index[v] == 0 {
+			t.connect(v)
+		}
+	}
+	return t.components
+}
+
+type tarjan[V comparable, W any] struct {
+	g          *Graph[V, W]
+	next       int
+	index      []int // 1-based discovery order, 0 for unvisited
+	low        []int
+	onStack    []bool
+	stack      []int
+	components [][]V
+}
+
+func (t *tarjan[V, W]) connect(v int) {
+	t.next++
+	t.index[v] = t.next
+	t.low[v] = t.next
+	t.stack = append(t.stack, v)
+	t.onStack[v] = true
+
+	for _, e := range t.g.adj[v] {
+		w := t.g.index[e.To]
+		if t.index[w] == 0 {
+			t.connect(w)
+			if t.low[w] < t.low[v] {
+				t.low[v] = t.low[w]
+			}
+		} else if t.onStack[w] && t.index[w] < t.low[v] {
+			t.low[v] = t.index[w]
+		}
+	}
+
+	if t.low[v] != t.index[v] {
+		return
+	}
+	var component []V
+	for {
+		w := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[w] = false
+		component = append(component, t.g.vertices[w])
+		if w == v {
+			break
+		}
+	}
+	t.components = append(t.components, component)
+}
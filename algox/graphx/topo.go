@@ -0,0 +1,110 @@
+package graphx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/unsafe-risk/utilx/dsx/linkedx/dequex"
+)
+
+var ErrCycle = errors.New("graph has a cycle")
+
+// CycleError is returned by TopologicalSort. Cycle lists the vertices of one
+// cycle in edge order; the last vertex has an edge back to the first.
+type CycleError[V comparable] struct {
+	Cycle []V
+}
+
+func (e *CycleError[V]) Error() string {
+	return fmt.Sprintf("%s: %v", ErrCycle, e.Cycle)
+}
+
+func (e *CycleError[V]) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// TopologicalSort orders the vertices of a directed graph so that every edge
+// points forward, using Kahn's algorithm. Ties are broken by insertion order.
+// If the graph has a cycle it returns a *CycleError.
+func TopologicalSort[V comparable, W any](g *Graph[V, W]) ([]V, error) {
+	indegree := make([]int, g.Len())
+	for _, adj := range g.adj {
+		for _, e := range adj {
+			indegree[g.index[e.To]]++
+		}
+	}
+
+	queue := dequex.New[int]()
+	for v, d := range indegree {
+		if d == 0 {
+			queue.PushBack(v)
+		}
+	}
+
+	order := make([]V, 0, g.Len())
+	for {
+		v, ok := queue.PopFront()
+		if !ok {
+			break
+		}
+		order = append(order, g.vertices[v])
+		for _, e := range g.adj[v] {
+			t := g.index[e.To]
+			indegree[t]--
+			if indegree[t] == 0 {
+				queue.PushBack(t)
+			}
+		}
+	}
+
+	if len(order) < g.Len() {
+		return nil, &CycleError[V]{Cycle: findCycle(g, indegree)}
+	}
+	return order, nil
+}
+
+// findCycle walks backwards over vertices left with a positive indegree by
+// Kahn's algorithm. Each of them has a predecessor among them, so the walk
+// must revisit a vertex.
+func findCycle[V comparable, W any](g *Graph[V, W], indegree []int) []V {
+	pred := make([]int, g.Len())
+	for i := range pred {
+		pred[i] = -1
+	}
+	start := -1
+	for f, adj := range g.adj {
+		if indegree[f] == 0 {
+			continue
+		}
+		for _, e := range adj {
+			t := g.index[e.To]
+			if indegree[t] > 0 && pred[t] == -1 {
+				pred[t] = f
+			}
+		}
+		if start == -1 {
+			start = f
+		}
+	}
+
+	step := make([]int, g.Len())
+	v := start
+	for n := 1; step[v] == 0; n++ {
+		step[v] = n
+		v = pred[v]
+	}
+
+	// v is on the cycle; collect it backwards and reverse
+	var cycle []V
+	for u := v; ; {
+		cycle = append(cycle, g.vertices[u])
+		u = pred[u]
+		if u == v {
+			break
+		}
+	}
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return cycle
+}
@@ -0,0 +1,73 @@
+package graphx
+
+import (
+	"github.com/unsafe-risk/utilx/dsx/linkedx/dequex"
+	"github.com/unsafe-risk/utilx/dsx/linkedx/queuex"
+)
+
+// bfsItem is a vertex index in the BFS queue with its depth.
+type bfsItem struct{ v, depth int }
+
+// BFS visits the vertices reachable from start in breadth-first order,
+// passing each vertex with its distance in edges from start. It stops when
+// visit returns false.
+func BFS[V comparable, W any](g *Graph[V, W], start V, visit func(v V, depth int) bool) {
+	s, ok := g.index[start]
+	if !ok {
+		return
+	}
+
+	seen := make([]bool, g.Len())
+	queue := queuex.New[bfsItem]()
+	seen[s] = true
+	queue.Enqueue(bfsItem{s, 0})
+	for {
+		it, ok := queue.Dequeue()
+		if !ok {
+			return
+		}
+		if !visit(g.vertices[it.v], it.depth) {
+			return
+		}
+		for _, e := range g.adj[it.v] {
+			t := g.index[e.To]
+			if !seen[t] {
+				seen[t] = true
+				queue.Enqueue(bfsItem{t, it.depth + 1})
+			}
+		}
+	}
+}
+
+// DFS visits the vertices reachable from start in depth-first preorder,
+// following edges in insertion order. It stops when visit returns false.
+func DFS[V comparable, W any](g *Graph[V, W], start V, visit func(v V) bool) {
+	s, ok := g.index[start]
+	if !ok {
+		return
+	}
+
+	seen := make([]bool, g.Len())
+	stack := dequex.New[int]()
+	stack.PushBack(s)
+	for {
+		v, ok := stack.PopBack()
+		if !ok {
+			return
+		}
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		if !visit(g.vertices[v]) {
+			return
+		}
+		// push in reverse so the first edge is explored first
+		adj := g.adj[v]
+		for i := len(adj) - 1; i >= 0; i-- {
+			if t := g.index[adj[i].To]; !seen[t] {
+				stack.PushBack(t)
+			}
+		}
+	}
+}
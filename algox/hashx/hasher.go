@@ -0,0 +1,100 @@
+package hashx
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+type keyKind uint8
+
+const (
+	kindSigned keyKind = iota
+	kindUnsigned
+	kindString
+	kindBytes
+)
+
+// Hasher hashes keys of a comparable type with a fixed seed. K may be any
+// integer type, a string type or a byte array type such as [16]byte, including
+// named types of these. Hashes only depend on the seed and the key value, so
+// they are stable across runs, processes and platforms:
+//   - integers are widened to 64 bits, so int32(-1) and int64(-1) hash alike,
+//     and hashed with WyHashUint64
+//   - strings are hashed with WyHashString
+//   - byte arrays are hashed with WyHash over their bytes
+//
+// The zero Hasher is not usable; use NewHasher.
+type Hasher[K comparable] struct {
+	seed uint64
+	kind keyKind
+	size uintptr
+}
+
+// NewHasher returns a Hasher for K. It panics if K is not one of the
+// supported kinds.
+func NewHasher[K comparable](seed uint64) Hasher[K] {
+	var zero K
+	h := Hasher[K]{seed: seed, size: unsafe.Sizeof(zero)}
+	t := reflect.TypeOf(zero)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.kind = kindSigned
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.kind = kindUnsigned
+	case reflect.String:
+		h.kind = kindString
+	case reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			panic(fmt.Sprintf("hashx: unsupported key type %v", t))
+		}
+		h.kind = kindBytes
+	default:
+		panic(fmt.Sprintf("hashx: unsupported key type %v", t))
+	}
+	return h
+}
+
+func (h Hasher[K]) Hash(k K) uint64 {
+	p := unsafe.Pointer(&k)
+	switch h.kind {
+	case kindSigned:
+		return WyHashUint64(uint64(loadSigned(p, h.size)), h.seed)
+	case kindUnsigned:
+		return WyHashUint64(loadUnsigned(p, h.size), h.seed)
+	case kindString:
+		return wyhash(bytesOf(*(*string)(p)), h.seed)
+	default:
+		return wyhash(unsafe.Slice((*byte)(p), h.size), h.seed)
+	}
+}
+
+func (h Hasher[K]) Seed() uint64 {
+	return h.seed
+}
+
+func loadSigned(p unsafe.Pointer, size uintptr) int64 {
+	switch size {
+	case 1:
+		return int64(*(*int8)(p))
+	case 2:
+		return int64(*(*int16)(p))
+	case 4:
+		return int64(*(*int32)(p))
+	default:
+		return *(*int64)(p)
+	}
+}
+
+func loadUnsigned(p unsafe.Pointer, size uintptr) uint64 {
+	switch size {
+	case 1:
+		return uint64(*(*uint8)(p))
+	case 2:
+		return uint64(*(*uint16)(p))
+	case 4:
+		return uint64(*(*uint32)(p))
+	default:
+		return *(*uint64)(p)
+	}
+}
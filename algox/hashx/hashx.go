@@ -0,0 +1,29 @@
+// Package hashx implements fast non-cryptographic hashes.
+package hashx
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"unsafe"
+)
+
+// bytesOf returns the bytes of s without copying them. They must not be
+// modified.
+func bytesOf(s string) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	return unsafe.Slice(*(**byte)(unsafe.Pointer(&s)), len(s))
+}
+
+func le64(p []byte, i int) uint64 {
+	return binary.LittleEndian.Uint64(p[i:])
+}
+
+func le32(p []byte, i int) uint64 {
+	return uint64(binary.LittleEndian.Uint32(p[i:]))
+}
+
+func rotl(x uint64, k int) uint64 {
+	return bits.RotateLeft64(x, k)
+}
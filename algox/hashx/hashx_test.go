@@ -0,0 +1,159 @@
+package hashx_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/hashx"
+)
+
+var inputs = []string{
+	"",
+	"a",
+	"abc",
+	"message digest",
+	"abcdefghijklmnopqrstuvwxyz",
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+	"12345678901234567890123456789012345678901234567890123456789012345678901234567890",
+}
+
+func TestXXHash64(t *testing.T) {
+	want := []uint64{
+		0xef46db3751d8e999,
+		0xd24ec4f1a98c6e5b,
+		0x44bc2cf5ad770999,
+		0x066ed728fceeb3be,
+		0xcfe1f278fa89835c,
+		0xaaa46907d3047814,
+		0xe04a477f19ee145d,
+	}
+	for i, s := range inputs {
+		if got := hashx.XXHash64String(s, 0); got != want[i] {
+			t.Errorf("XXHash64String(%q) = %#x, want %#x", s, got, want[i])
+		}
+		if got := hashx.XXHash64([]byte(s), 0); got != want[i] {
+			t.Errorf("XXHash64(%q) = %#x, want %#x", s, got, want[i])
+		}
+	}
+	if hashx.XXHash64String("abc", 1) == want[2] {
+		t.Error("seed is ignored")
+	}
+}
+
+func TestXXHash64Stream(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for n := 0; n <= len(data); n += 37 {
+		want := hashx.XXHash64(data[:n], 42)
+		for _, chunk := range []int{1, 5, 31, 32, 33, 100} {
+			h := hashx.NewXXHash64(42)
+			for i := 0; i < n; i += chunk {
+				end := i + chunk
+				if end > n {
+					end = n
+				}
+				h.Write(data[i:end])
+			}
+			if got := h.Sum64(); got != want {
+				t.Fatalf("len %d in chunks of %d: got %#x, want %#x", n, chunk, got, want)
+			}
+			if got := binary.BigEndian.Uint64(h.Sum(nil)); got != want {
+				t.Fatalf("Sum does not match Sum64")
+			}
+		}
+	}
+}
+
+func TestWyHash(t *testing.T) {
+	// test vectors of wyhash final 3, seeded with the input index
+	want := []uint64{
+		0x42bc986dc5eec4d3,
+		0x84508dc903c31551,
+		0x0bc54887cfc9ecb1,
+		0x6e2ff3298208a67c,
+		0x9a64e42e897195b9,
+		0x9199383239c32554,
+		0x7c1ccf6bba30f5a5,
+	}
+	for i, s := range inputs {
+		if got := hashx.WyHashString(s, uint64(i)); got != want[i] {
+			t.Errorf("WyHashString(%q, %d) = %#x, want %#x", s, i, got, want[i])
+		}
+		if got := hashx.WyHash([]byte(s), uint64(i)); got != want[i] {
+			t.Errorf("WyHash(%q, %d) = %#x, want %#x", s, i, got, want[i])
+		}
+	}
+
+	for _, v := range []uint64{0, 1, 0x0123456789abcdef, 1<<64 - 1} {
+		b := binary.LittleEndian.AppendUint64(nil, v)
+		if got, want := hashx.WyHashUint64(v, 9), hashx.WyHash(b, 9); got != want {
+			t.Errorf("WyHashUint64(%#x) = %#x, want %#x", v, got, want)
+		}
+	}
+}
+
+type hexString string
+
+func TestHasher(t *testing.T) {
+	const seed = 7
+
+	if got, want := hashx.NewHasher[int8](seed).Hash(-2), hashx.NewHasher[int64](seed).Hash(-2); got != want {
+		t.Errorf("int8 and int64 differ: %#x != %#x", got, want)
+	}
+	if got, want := hashx.NewHasher[uint16](seed).Hash(500), hashx.WyHashUint64(500, seed); got != want {
+		t.Errorf("uint16: got %#x, want %#x", got, want)
+	}
+	if got, want := hashx.NewHasher[int](seed).Hash(-1), hashx.WyHashUint64(1<<64-1, seed); got != want {
+		t.Errorf("int: got %#x, want %#x", got, want)
+	}
+	if got, want := hashx.NewHasher[hexString](seed).Hash("abc"), hashx.WyHashString("abc", seed); got != want {
+		t.Errorf("named string: got %#x, want %#x", got, want)
+	}
+	id := [4]byte{1, 2, 3, 4}
+	if got, want := hashx.NewHasher[[4]byte](seed).Hash(id), hashx.WyHash(id[:], seed); got != want {
+		t.Errorf("byte array: got %#x, want %#x", got, want)
+	}
+	if hashx.NewHasher[string](1).Hash("abc") == hashx.NewHasher[string](2).Hash("abc") {
+		t.Error("seed is ignored")
+	}
+
+	for _, f := range []func(){
+		func() { hashx.NewHasher[float64](0) },
+		func() { hashx.NewHasher[[2]int](0) },
+		func() { hashx.NewHasher[struct{}](0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("unsupported key type did not panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func BenchmarkXXHash64(b *testing.B) {
+	data := make([]byte, 1024)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		hashx.XXHash64(data, 0)
+	}
+}
+
+func BenchmarkWyHash(b *testing.B) {
+	data := make([]byte, 1024)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		hashx.WyHash(data, 0)
+	}
+}
+
+func BenchmarkHasherString(b *testing.B) {
+	h := hashx.NewHasher[string](0)
+	for i := 0; i < b.N; i++ {
+		h.Hash("user:123456")
+	}
+}
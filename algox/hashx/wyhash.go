@@ -0,0 +1,67 @@
+package hashx
+
+import "math/bits"
+
+// wyhash final version 3 by Wang Yi, https://github.com/wangyi-fudan/wyhash
+// (public domain), with the default secret.
+
+var wySecret = [4]uint64{0xa0761d6478bd642f, 0xe7037ed1a0b428db, 0x8ebc6af09c88c6e3, 0x589965cc75374cc3}
+
+// WyHash returns the wyhash of b with the given seed. It is usually faster
+// than XXHash64 on short keys.
+func WyHash(b []byte, seed uint64) uint64 {
+	return wyhash(b, seed)
+}
+
+func WyHashString(s string, seed uint64) uint64 {
+	return wyhash(bytesOf(s), seed)
+}
+
+func wyhash(p []byte, seed uint64) uint64 {
+	n := len(p)
+	seed ^= wySecret[0]
+	var a, b uint64
+	switch {
+	case n <= 16:
+		if n >= 4 {
+			q := (n >> 3) << 2
+			a = le32(p, 0)<<32 | le32(p, q)
+			b = le32(p, n-4)<<32 | le32(p, n-4-q)
+		} else if n > 0 {
+			a = uint64(p[0])<<16 | uint64(p[n>>1])<<8 | uint64(p[n-1])
+		}
+	default:
+		i, rest := 0, n
+		if rest > 48 {
+			see1, see2 := seed, seed
+			for rest > 48 {
+				seed = wyMix(le64(p, i)^wySecret[1], le64(p, i+8)^seed)
+				see1 = wyMix(le64(p, i+16)^wySecret[2], le64(p, i+24)^see1)
+				see2 = wyMix(le64(p, i+32)^wySecret[3], le64(p, i+40)^see2)
+				i += 48
+				rest -= 48
+			}
+			seed ^= see1 ^ see2
+		}
+		for rest > 16 {
+			seed = wyMix(le64(p, i)^wySecret[1], le64(p, i+8)^seed)
+			i += 16
+			rest -= 16
+		}
+		a = le64(p, i+rest-16)
+		b = le64(p, i+rest-8)
+	}
+	return wyMix(wySecret[1]^uint64(n), wyMix(a^wySecret[1], b^seed))
+}
+
+// WyHashUint64 is WyHash of the 8 little-endian bytes of v, without the
+// encoding.
+func WyHashUint64(v, seed uint64) uint64 {
+	lo, hi := v&0xffffffff, v>>32
+	return wyMix(wySecret[1]^8, wyMix((lo<<32|hi)^wySecret[1], (hi<<32|lo)^seed^wySecret[0]))
+}
+
+func wyMix(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return hi ^ lo
+}
@@ -0,0 +1,160 @@
+package hashx
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+// xxHash64 by Yann Collet, https://github.com/Cyan4973/xxHash (BSD 2-Clause).
+
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// XXHash64 returns the XXH64 hash of b with the given seed.
+func XXHash64(b []byte, seed uint64) uint64 {
+	return xxhash64(b, seed)
+}
+
+func XXHash64String(s string, seed uint64) uint64 {
+	return xxhash64(bytesOf(s), seed)
+}
+
+func xxhash64(p []byte, seed uint64) uint64 {
+	n := len(p)
+	var h uint64
+	i := 0
+	if n >= 32 {
+		v1, v2, v3, v4 := seed+xxPrime1+xxPrime2, seed+xxPrime2, seed, seed-xxPrime1
+		for ; i+32 <= n; i += 32 {
+			v1 = xxRound(v1, le64(p, i))
+			v2 = xxRound(v2, le64(p, i+8))
+			v3 = xxRound(v3, le64(p, i+16))
+			v4 = xxRound(v4, le64(p, i+24))
+		}
+		h = xxMerge(v1, v2, v3, v4)
+	} else {
+		h = seed + xxPrime5
+	}
+	h += uint64(n)
+	return xxFinish(h, p[i:])
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = rotl(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMergeRound(acc, v uint64) uint64 {
+	acc ^= xxRound(0, v)
+	return acc*xxPrime1 + xxPrime4
+}
+
+func xxMerge(v1, v2, v3, v4 uint64) uint64 {
+	h := rotl(v1, 1) + rotl(v2, 7) + rotl(v3, 12) + rotl(v4, 18)
+	h = xxMergeRound(h, v1)
+	h = xxMergeRound(h, v2)
+	h = xxMergeRound(h, v3)
+	return xxMergeRound(h, v4)
+}
+
+// xxFinish mixes in the last len(p) < 32 bytes and avalanches h.
+func xxFinish(h uint64, p []byte) uint64 {
+	i := 0
+	for ; i+8 <= len(p); i += 8 {
+		h ^= xxRound(0, le64(p, i))
+		h = rotl(h, 27)*xxPrime1 + xxPrime4
+	}
+	if i+4 <= len(p) {
+		h ^= le32(p, i) * xxPrime1
+		h = rotl(h, 23)*xxPrime2 + xxPrime3
+		i += 4
+	}
+	for ; i < len(p); i++ {
+		h ^= uint64(p[i]) * xxPrime5
+		h = rotl(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
+
+// NewXXHash64 returns a streaming XXH64 hash. Its Sum64 equals XXHash64 of
+// everything written so far.
+func NewXXHash64(seed uint64) hash.Hash64 {
+	d := &xxDigest{seed: seed}
+	d.Reset()
+	return d
+}
+
+type xxDigest struct {
+	seed           uint64
+	v1, v2, v3, v4 uint64
+	total          uint64
+	buf            [32]byte
+	n              int // bytes buffered in buf
+}
+
+func (d *xxDigest) Reset() {
+	d.v1 = d.seed + xxPrime1 + xxPrime2
+	d.v2 = d.seed + xxPrime2
+	d.v3 = d.seed
+	d.v4 = d.seed - xxPrime1
+	d.total = 0
+	d.n = 0
+}
+
+func (d *xxDigest) Size() int      { return 8 }
+func (d *xxDigest) BlockSize() int { return 32 }
+
+func (d *xxDigest) Write(b []byte) (int, error) {
+	written := len(b)
+	d.total += uint64(written)
+
+	if d.n+len(b) < 32 {
+		d.n += copy(d.buf[d.n:], b)
+		return written, nil
+	}
+	if d.n > 0 {
+		c := copy(d.buf[d.n:], b)
+		d.stripe(d.buf[:])
+		b = b[c:]
+		d.n = 0
+	}
+	for ; len(b) >= 32; b = b[32:] {
+		d.stripe(b)
+	}
+	d.n = copy(d.buf[:], b)
+	return written, nil
+}
+
+func (d *xxDigest) stripe(b []byte) {
+	d.v1 = xxRound(d.v1, le64(b, 0))
+	d.v2 = xxRound(d.v2, le64(b, 8))
+	d.v3 = xxRound(d.v3, le64(b, 16))
+	d.v4 = xxRound(d.v4, le64(b, 24))
+}
+
+func (d *xxDigest) Sum64() uint64 {
+	var h uint64
+	if d.total >= 32 {
+		h = xxMerge(d.v1, d.v2, d.v3, d.v4)
+	} else {
+		h = d.seed + xxPrime5
+	}
+	h += d.total
+	return xxFinish(h, d.buf[:d.n])
+}
+
+func (d *xxDigest) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, d.Sum64())
+}
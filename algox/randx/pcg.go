@@ -0,0 +1,95 @@
+package randx
+
+import "math/bits"
+
+// PCG is a 128-bit permuted congruential generator with the DXSM output
+// function, the same generator as math/rand/v2.PCG. It can jump to any point
+// of its 2^128 period with Advance.
+type PCG struct {
+	hi, lo uint64
+}
+
+const (
+	pcgMulHi = 2549297995355413924
+	pcgMulLo = 4865540595714422341
+	pcgIncHi = 6364136223846793005
+	pcgIncLo = 1442695040888963407
+)
+
+func NewPCG(seed1, seed2 uint64) *PCG {
+	return &PCG{hi: seed1, lo: seed2}
+}
+
+func (p *PCG) next() (hi, lo uint64) {
+	// state = state*mul + inc, mod 2^128
+	hi, lo = bits.Mul64(p.lo, pcgMulLo)
+	hi += p.hi*pcgMulLo + p.lo*pcgMulHi
+	lo, c := bits.Add64(lo, pcgIncLo, 0)
+	hi, _ = bits.Add64(hi, pcgIncHi, c)
+	p.hi, p.lo = hi, lo
+	return hi, lo
+}
+
+func (p *PCG) Uint64() uint64 {
+	hi, lo := p.next()
+	const cheapMul = 0xda942042e4dd58b5
+	hi ^= hi >> 32
+	hi *= cheapMul
+	hi ^= hi >> 48
+	hi *= lo | 1
+	return hi
+}
+
+func (p *PCG) Int63() int64 {
+	return int64(p.Uint64() >> 1)
+}
+
+// Seed sets the state to (0, seed).
+func (p *PCG) Seed(seed int64) {
+	p.hi, p.lo = 0, uint64(seed)
+}
+
+// Advance moves p forward by delta steps in O(log delta) time.
+func (p *PCG) Advance(delta uint64) {
+	// Brown, "Random Number Generation with Arbitrary Stride"
+	accMulHi, accMulLo := uint64(0), uint64(1)
+	accIncHi, accIncLo := uint64(0), uint64(0)
+	curMulHi, curMulLo := uint64(pcgMulHi), uint64(pcgMulLo)
+	curIncHi, curIncLo := uint64(pcgIncHi), uint64(pcgIncLo)
+	for delta > 0 {
+		if delta&1 != 0 {
+			accMulHi, accMulLo = mul128(accMulHi, accMulLo, curMulHi, curMulLo)
+			accIncHi, accIncLo = mul128(accIncHi, accIncLo, curMulHi, curMulLo)
+			accIncHi, accIncLo = add128(accIncHi, accIncLo, curIncHi, curIncLo)
+		}
+		// cur_inc = (cur_mul + 1) * cur_inc; cur_mul = cur_mul^2
+		m1Hi, m1Lo := add128(curMulHi, curMulLo, 0, 1)
+		curIncHi, curIncLo = mul128(m1Hi, m1Lo, curIncHi, curIncLo)
+		curMulHi, curMulLo = mul128(curMulHi, curMulLo, curMulHi, curMulLo)
+		delta >>= 1
+	}
+	p.hi, p.lo = mul128(accMulHi, accMulLo, p.hi, p.lo)
+	p.hi, p.lo = add128(p.hi, p.lo, accIncHi, accIncLo)
+}
+
+// Split returns a copy of p and advances p by 2^64 steps, so the two streams
+// do not overlap for the first 2^64 outputs.
+func (p *PCG) Split() Source {
+	c := *p
+	// two steps of 2^63 since Advance takes a uint64
+	p.Advance(1 << 63)
+	p.Advance(1 << 63)
+	return &c
+}
+
+func mul128(aHi, aLo, bHi, bLo uint64) (hi, lo uint64) {
+	hi, lo = bits.Mul64(aLo, bLo)
+	hi += aHi*bLo + aLo*bHi
+	return hi, lo
+}
+
+func add128(aHi, aLo, bHi, bLo uint64) (hi, lo uint64) {
+	lo, c := bits.Add64(aLo, bLo, 0)
+	hi, _ = bits.Add64(aHi, bHi, c)
+	return hi, lo
+}
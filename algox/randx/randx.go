@@ -0,0 +1,74 @@
+// Package randx provides seedable and splittable random sources.
+package randx
+
+import (
+	"math/bits"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// Source is a seedable pseudo-random generator. Every Source is a
+// rand.Source64, so rand.New(src) gives the full math/rand API on top of it.
+//
+// Sources are deterministic for a given seed on every platform and are not
+// safe for concurrent use; Split one per goroutine instead.
+type Source interface {
+	rand.Source64
+	// Split returns a new generator whose stream does not overlap with the
+	// rest of this one, advancing this one.
+	Split() Source
+}
+
+var _ Source = (*SplitMix64)(nil)
+var _ Source = (*Xoshiro256)(nil)
+var _ Source = (*PCG)(nil)
+
+var rootSeed uint64
+
+func init() {
+	rootSeed = Mix64(uint64(time.Now().UnixNano()))
+}
+
+// Seed returns a seed that differs on every call, for sources that do not
+// need to be reproducible. It is safe for concurrent use.
+func Seed() uint64 {
+	return Mix64(atomic.AddUint64(&rootSeed, splitMixGamma))
+}
+
+// Uint64n returns a uniform value in [0, n) without modulo bias.
+// It panics if n == 0.
+func Uint64n(src Source, n uint64) uint64 {
+	if n == 0 {
+		panic("randx: invalid argument to Uint64n")
+	}
+	// Lemire's multiply-shift with rejection
+	hi, lo := bits.Mul64(src.Uint64(), n)
+	if lo < n {
+		thresh := -n % n
+		for lo < thresh {
+			hi, lo = bits.Mul64(src.Uint64(), n)
+		}
+	}
+	return hi
+}
+
+// Intn returns a uniform value in [0, n). It panics if n <= 0.
+func Intn(src Source, n int) int {
+	if n <= 0 {
+		panic("randx: invalid argument to Intn")
+	}
+	return int(Uint64n(src, uint64(n)))
+}
+
+// Float64 returns a uniform value in [0, 1).
+func Float64(src Source) float64 {
+	return float64(src.Uint64()>>11) / (1 << 53)
+}
+
+// Shuffle randomizes the order of n elements with a Fisher-Yates shuffle.
+func Shuffle(src Source, n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, Intn(src, i+1))
+	}
+}
@@ -0,0 +1,91 @@
+package randx
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSplitMix64(t *testing.T) {
+	s := NewSplitMix64(0)
+	for _, want := range []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4} {
+		if v := s.Uint64(); v != want {
+			t.Fatalf("SplitMix64 = %#x, want %#x", v, want)
+		}
+	}
+}
+
+func TestXoshiro256(t *testing.T) {
+	x := &Xoshiro256{s: [4]uint64{1, 2, 3, 4}}
+	for _, want := range []uint64{0x2d00, 0, 0x5a007080} {
+		if v := x.Uint64(); v != want {
+			t.Fatalf("Xoshiro256 = %#x, want %#x", v, want)
+		}
+	}
+	x.Jump()
+	if v := x.Uint64(); v != 0xa0425028ca8b66a0 {
+		t.Fatalf("Xoshiro256 after Jump = %#x", v)
+	}
+
+	a := NewXoshiro256(42)
+	b := a.Split()
+	if a.Uint64() == b.Uint64() {
+		t.Fatal("split streams start with the same value")
+	}
+}
+
+func TestPCG(t *testing.T) {
+	// same outputs as math/rand/v2.NewPCG(1, 2)
+	p := NewPCG(1, 2)
+	for _, want := range []uint64{0xc4f5a58656eef510, 0x9dcec3ad077dec6c, 0xc8d04605312f8088} {
+		if v := p.Uint64(); v != want {
+			t.Fatalf("PCG = %#x, want %#x", v, want)
+		}
+	}
+
+	a, b := NewPCG(7, 8), NewPCG(7, 8)
+	for i := 0; i < 1000; i++ {
+		a.Uint64()
+	}
+	b.Advance(1000)
+	if a.Uint64() != b.Uint64() {
+		t.Fatal("Advance(1000) differs from 1000 steps")
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	var src Source = NewXoshiro256(1)
+	counts := make([]int, 6)
+	for i := 0; i < 60000; i++ {
+		counts[Intn(src, 6)]++
+	}
+	for i, c := range counts {
+		if c < 9000 || c > 11000 {
+			t.Fatalf("Intn bucket %d has %d hits", i, c)
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		if f := Float64(src); f < 0 || f >= 1 {
+			t.Fatalf("Float64 = %v", f)
+		}
+	}
+
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	Shuffle(NewPCG(1, 1), len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+	s2 := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	Shuffle(NewPCG(1, 1), len(s2), func(i, j int) { s2[i], s2[j] = s2[j], s2[i] })
+	for i := range s {
+		if s[i] != s2[i] {
+			t.Fatal("Shuffle is not deterministic for a seed")
+		}
+	}
+
+	// works as a math/rand source
+	r := rand.New(NewSplitMix64(3))
+	if n := r.Intn(10); n < 0 || n >= 10 {
+		t.Fatalf("rand.Intn = %d", n)
+	}
+	if Seed() == Seed() {
+		t.Fatal("Seed returned the same value twice")
+	}
+}
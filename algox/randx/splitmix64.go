@@ -0,0 +1,52 @@
+package randx
+
+/******************************************************************************
+
+Original C code: https://xorshift.di.unimi.it/splitmix64.c
+Written in 2015 by Sebastiano Vigna (vigna@acm.org)
+
+To the extent possible under law, the author has dedicated all copyright
+and related and neighboring rights to this software to the public domain
+worldwide. This software is distributed without any warranty.
+
+See <http://creativecommons.org/publicdomain/zero/1.0/>.
+
+*******************************************************************************/
+
+const splitMixGamma = 0x9e3779b97f4a7c15
+
+// Mix64 is the splitmix64 output function, a bijective 64-bit mixer.
+func Mix64(x0 uint64) uint64 {
+	x0 = (x0 ^ (x0 >> 30)) * 0xbf58476d1ce4e5b9
+	x0 = (x0 ^ (x0 >> 27)) * 0x94d049bb133111eb
+	return x0 ^ (x0 >> 31)
+}
+
+// SplitMix64 is a tiny and fast generator with 64 bits of state. Its period is
+// 2^64, so it suits seeding other generators and short streams.
+type SplitMix64 struct {
+	state uint64
+}
+
+func NewSplitMix64(seed uint64) *SplitMix64 {
+	return &SplitMix64{state: seed}
+}
+
+func (s *SplitMix64) Uint64() uint64 {
+	s.state += splitMixGamma
+	return Mix64(s.state)
+}
+
+func (s *SplitMix64) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s *SplitMix64) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// Split returns a generator seeded from the next output of s. The streams are
+// statistically independent, though not guaranteed disjoint.
+func (s *SplitMix64) Split() Source {
+	return NewSplitMix64(s.Uint64())
+}
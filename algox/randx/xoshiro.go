@@ -0,0 +1,90 @@
+package randx
+
+import "math/bits"
+
+/******************************************************************************
+
+Original C code: https://prng.di.unimi.it/xoshiro256starstar.c
+Written in 2018 by David Blackman and Sebastiano Vigna (vigna@acm.org)
+
+To the extent possible under law, the author has dedicated all copyright
+and related and neighboring rights to this software to the public domain
+worldwide. This software is distributed without any warranty.
+
+See <http://creativecommons.org/publicdomain/zero/1.0/>.
+
+*******************************************************************************/
+
+// Xoshiro256 is the xoshiro256** generator: 256 bits of state, a period of
+// 2^256-1 and jump functions for parallel streams.
+type Xoshiro256 struct {
+	s [4]uint64
+}
+
+// NewXoshiro256 seeds the state with SplitMix64, as the authors recommend.
+func NewXoshiro256(seed uint64) *Xoshiro256 {
+	x := &Xoshiro256{}
+	x.Seed(int64(seed))
+	return x
+}
+
+func (x *Xoshiro256) Uint64() uint64 {
+	s := &x.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+func (x *Xoshiro256) Int63() int64 {
+	return int64(x.Uint64() >> 1)
+}
+
+func (x *Xoshiro256) Seed(seed int64) {
+	sm := SplitMix64{state: uint64(seed)}
+	for i := range x.s {
+		x.s[i] = sm.Uint64()
+	}
+}
+
+// Jump advances x by 2^128 steps, giving 2^128 non-overlapping subsequences.
+func (x *Xoshiro256) Jump() {
+	x.jump([4]uint64{0x180ec6d33cfd0aba, 0xd5a61266f0c9392c, 0xa9582618e03fc9aa, 0x39abdc4529b1661c})
+}
+
+// LongJump advances x by 2^192 steps, giving 2^64 starting points that can
+// each be split further with Jump.
+func (x *Xoshiro256) LongJump() {
+	x.jump([4]uint64{0x76e15d3efefdcbbf, 0xc5004e441c522fb3, 0x77710069854ee241, 0x39109bb02acbe635})
+}
+
+// Split returns a copy of x and jumps x ahead by 2^128 steps, so the two
+// streams do not overlap.
+func (x *Xoshiro256) Split() Source {
+	c := *x
+	x.Jump()
+	return &c
+}
+
+func (x *Xoshiro256) jump(poly [4]uint64) {
+	var s [4]uint64
+	for _, p := range poly {
+		for b := 0; b < 64; b++ {
+			if p&(1<<b) != 0 {
+				s[0] ^= x.s[0]
+				s[1] ^= x.s[1]
+				s[2] ^= x.s[2]
+				s[3] ^= x.s[3]
+			}
+			x.Uint64()
+		}
+	}
+	x.s = s
+}
@@ -0,0 +1,83 @@
+package samplex
+
+import (
+	"math"
+
+	"github.com/unsafe-risk/utilx/algox/randx"
+)
+
+// Alias picks indices in proportion to fixed weights in O(1) time with
+// Walker's alias method, built in O(n) with Vose's algorithm.
+//
+// Alias is not safe for concurrent use because of its source; share the
+// table with Clone instead.
+type Alias struct {
+	src   randx.Source
+	prob  []float64
+	alias []int
+}
+
+func NewAlias(weights []float64, src randx.Source) (*Alias, error) {
+	var sum float64
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, ErrInvalidWeight
+		}
+		sum += w
+	}
+	if sum <= 0 || math.IsInf(sum, 0) {
+		return nil, ErrInvalidWeight
+	}
+
+	n := len(weights)
+	a := &Alias{src: src, prob: make([]float64, n), alias: make([]int, n)}
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / sum
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		a.prob[s] = scaled[s]
+		a.alias[s] = l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// what is left is 1 up to rounding errors
+	for _, i := range large {
+		a.prob[i] = 1
+	}
+	for _, i := range small {
+		a.prob[i] = 1
+	}
+	return a, nil
+}
+
+// Pick returns an index with probability weights[i] / sum(weights).
+func (a *Alias) Pick() int {
+	i := randx.Intn(a.src, len(a.prob))
+	if randx.Float64(a.src) < a.prob[i] {
+		return i
+	}
+	return a.alias[i]
+}
+
+// Len returns the number of weights.
+func (a *Alias) Len() int {
+	return len(a.prob)
+}
+
+// Clone returns an Alias sharing the table of a with its own source.
+func (a *Alias) Clone(src randx.Source) *Alias {
+	return &Alias{src: src, prob: a.prob, alias: a.alias}
+}
@@ -0,0 +1,110 @@
+// Package samplex draws random samples from slices and streams.
+package samplex
+
+import (
+	"math"
+
+	"github.com/unsafe-risk/utilx/algox/randx"
+)
+
+// Reservoir keeps a uniform random sample of up to k items from a stream of
+// unknown length with Vitter's Algorithm R. Every item costs one random draw.
+//
+// Reservoir is not safe for concurrent use.
+type Reservoir[T any] struct {
+	src    randx.Source
+	k      int
+	seen   int64
+	sample []T
+}
+
+func NewReservoir[T any](k int, src randx.Source) *Reservoir[T] {
+	if k < 0 {
+		k = 0
+	}
+	return &Reservoir[T]{src: src, k: k, sample: make([]T, 0, k)}
+}
+
+func (r *Reservoir[T]) Add(v T) {
+	r.seen++
+	if len(r.sample) < r.k {
+		r.sample = append(r.sample, v)
+		return
+	}
+	if j := randx.Uint64n(r.src, uint64(r.seen)); j < uint64(r.k) {
+		r.sample[j] = v
+	}
+}
+
+// Sample returns the current sample. It does not reset r.
+func (r *Reservoir[T]) Sample() []T {
+	return append([]T(nil), r.sample...)
+}
+
+// Seen returns the number of items added so far.
+func (r *Reservoir[T]) Seen() int64 {
+	return r.seen
+}
+
+// ReservoirL is Reservoir with Li's Algorithm L, which computes how many items
+// to skip instead of drawing for each of them. It needs O(k log(n/k)) random
+// draws for n items, which makes it much faster on long streams.
+//
+// ReservoirL is not safe for concurrent use.
+type ReservoirL[T any] struct {
+	src    randx.Source
+	k      int
+	seen   int64
+	next   int64 // index of the next item to take
+	w      float64
+	sample []T
+}
+
+func NewReservoirL[T any](k int, src randx.Source) *ReservoirL[T] {
+	if k < 0 {
+		k = 0
+	}
+	return &ReservoirL[T]{src: src, k: k, sample: make([]T, 0, k)}
+}
+
+func (r *ReservoirL[T]) Add(v T) {
+	r.seen++
+	if r.k == 0 {
+		return
+	}
+	if len(r.sample) < r.k {
+		r.sample = append(r.sample, v)
+		if len(r.sample) == r.k {
+			r.w = math.Exp(math.Log(openFloat64(r.src)) / float64(r.k))
+			r.skip()
+		}
+		return
+	}
+	if r.seen < r.next {
+		return
+	}
+	r.sample[randx.Intn(r.src, r.k)] = v
+	r.w *= math.Exp(math.Log(openFloat64(r.src)) / float64(r.k))
+	r.skip()
+}
+
+func (r *ReservoirL[T]) skip() {
+	gap := math.Floor(math.Log(openFloat64(r.src)) / math.Log1p(-r.w))
+	if gap > math.MaxInt64/2 {
+		gap = math.MaxInt64 / 2
+	}
+	r.next = r.seen + int64(gap) + 1
+}
+
+func (r *ReservoirL[T]) Sample() []T {
+	return append([]T(nil), r.sample...)
+}
+
+func (r *ReservoirL[T]) Seen() int64 {
+	return r.seen
+}
+
+// openFloat64 returns a uniform value in (0, 1), safe to pass to math.Log.
+func openFloat64(src randx.Source) float64 {
+	return (float64(src.Uint64()>>11) + 0.5) / (1 << 53)
+}
@@ -0,0 +1,169 @@
+package samplex_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/randx"
+	"github.com/unsafe-risk/utilx/algox/samplex"
+)
+
+const trials = 20000
+
+type sampler interface {
+	Add(int)
+	Sample() []int
+}
+
+func checkUniform(t *testing.T, newSampler func(src randx.Source) sampler) {
+	const n, k = 20, 5
+	src := randx.NewSplitMix64(1)
+	counts := make([]int, n)
+	for i := 0; i < trials; i++ {
+		s := newSampler(src)
+		for v := 0; v < n; v++ {
+			s.Add(v)
+		}
+		got := s.Sample()
+		if len(got) != k {
+			t.Fatalf("sample has %d items, want %d", len(got), k)
+		}
+		seen := map[int]bool{}
+		for _, v := range got {
+			if seen[v] {
+				t.Fatalf("%d sampled twice in %v", v, got)
+			}
+			seen[v] = true
+			counts[v]++
+		}
+	}
+	want := float64(trials) * k / n
+	for v, c := range counts {
+		if math.Abs(float64(c)-want) > want*0.08 {
+			t.Errorf("item %d sampled %d times, want about %.0f", v, c, want)
+		}
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	checkUniform(t, func(src randx.Source) sampler {
+		return samplex.NewReservoir[int](5, src)
+	})
+
+	r := samplex.NewReservoir[int](5, randx.NewSplitMix64(1))
+	r.Add(1)
+	r.Add(2)
+	if got := r.Sample(); len(got) != 2 || got[0] != 1 || got[1] != 2 || r.Seen() != 2 {
+		t.Fatalf("short stream: got %v, seen %d", got, r.Seen())
+	}
+}
+
+func TestReservoirL(t *testing.T) {
+	checkUniform(t, func(src randx.Source) sampler {
+		return samplex.NewReservoirL[int](5, src)
+	})
+}
+
+func TestReservoirDeterministic(t *testing.T) {
+	run := func() []int {
+		r := samplex.NewReservoirL[int](10, randx.NewXoshiro256(42))
+		for i := 0; i < 100000; i++ {
+			r.Add(i)
+		}
+		return r.Sample()
+	}
+	a, b := run(), run()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed gave %v and %v", a, b)
+		}
+	}
+}
+
+func TestWeightedReservoir(t *testing.T) {
+	weights := []float64{1, 2, 3, 4, 0}
+	src := randx.NewSplitMix64(2)
+	counts := make([]int, len(weights))
+	for i := 0; i < trials; i++ {
+		r := samplex.NewWeightedReservoir[int](1, src)
+		for v, w := range weights {
+			if err := r.Add(v, w); err != nil {
+				t.Fatal(err)
+			}
+		}
+		counts[r.Sample()[0]]++
+	}
+	for v, w := range weights {
+		want := trials * w / 10
+		if math.Abs(float64(counts[v])-want) > trials*0.015 {
+			t.Errorf("item %d sampled %d times, want about %.0f", v, counts[v], want)
+		}
+	}
+
+	// without replacement, the heaviest item is almost always in a sample of 2
+	var heavy int
+	for i := 0; i < trials; i++ {
+		r := samplex.NewWeightedReservoir[int](2, src)
+		for v := 0; v < 100; v++ {
+			w := 1.0
+			if v == 37 {
+				w = 1000
+			}
+			r.Add(v, w)
+		}
+		got := r.Sample()
+		if len(got) != 2 || got[0] == got[1] {
+			t.Fatalf("bad sample %v", got)
+		}
+		if got[0] == 37 || got[1] == 37 {
+			heavy++
+		}
+	}
+	if heavy < trials*99/100 {
+		t.Errorf("heavy item sampled %d times out of %d", heavy, trials)
+	}
+
+	if err := samplex.NewWeightedReservoir[int](1, src).Add(0, math.NaN()); !errors.Is(err, samplex.ErrInvalidWeight) {
+		t.Errorf("NaN weight: got %v", err)
+	}
+}
+
+func TestAlias(t *testing.T) {
+	weights := []float64{5, 0, 1, 3, 1}
+	a, err := samplex.NewAlias(weights, randx.NewPCG(1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 100000
+	counts := make([]int, a.Len())
+	for i := 0; i < n; i++ {
+		counts[a.Pick()]++
+	}
+	for i, w := range weights {
+		want := n * w / 10
+		if math.Abs(float64(counts[i])-want) > n*0.01 {
+			t.Errorf("index %d picked %d times, want about %.0f", i, counts[i], want)
+		}
+	}
+
+	for _, bad := range [][]float64{nil, {0, 0}, {1, -1}, {math.Inf(1)}} {
+		if _, err := samplex.NewAlias(bad, randx.NewPCG(1, 2)); !errors.Is(err, samplex.ErrInvalidWeight) {
+			t.Errorf("NewAlias(%v): got %v", bad, err)
+		}
+	}
+}
+
+func BenchmarkReservoir(b *testing.B) {
+	r := samplex.NewReservoir[int](100, randx.NewXoshiro256(1))
+	for i := 0; i < b.N; i++ {
+		r.Add(i)
+	}
+}
+
+func BenchmarkReservoirL(b *testing.B) {
+	r := samplex.NewReservoirL[int](100, randx.NewXoshiro256(1))
+	for i := 0; i < b.N; i++ {
+		r.Add(i)
+	}
+}
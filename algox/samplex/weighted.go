@@ -0,0 +1,96 @@
+package samplex
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+
+	"github.com/unsafe-risk/utilx/algox/randx"
+)
+
+var ErrInvalidWeight = errors.New("weights must be finite and not negative, with a positive sum")
+
+// WeightedReservoir keeps a weighted random sample of up to k items without
+// replacement from a stream, using Efraimidis and Spirakis' A-ExpJ: an item's
+// chance to be picked is proportional to its weight, and only O(k log(n/k))
+// random draws are needed for n items.
+//
+// WeightedReservoir is not safe for concurrent use.
+type WeightedReservoir[T any] struct {
+	src  randx.Source
+	k    int
+	heap keyHeap[T]
+	// weight still to be skipped before the next replacement
+	skip float64
+}
+
+type keyed[T any] struct {
+	// log of the A-Res key u^(1/w), so small weights do not underflow
+	key   float64
+	value T
+}
+
+func NewWeightedReservoir[T any](k int, src randx.Source) *WeightedReservoir[T] {
+	if k < 0 {
+		k = 0
+	}
+	return &WeightedReservoir[T]{src: src, k: k, heap: make(keyHeap[T], 0, k)}
+}
+
+// Add offers v with the given weight. Items with a weight <= 0 are never
+// sampled. It returns ErrInvalidWeight for a NaN or infinite weight.
+func (r *WeightedReservoir[T]) Add(v T, weight float64) error {
+	if math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return ErrInvalidWeight
+	}
+	if weight <= 0 || r.k == 0 {
+		return nil
+	}
+
+	if len(r.heap) < r.k {
+		heap.Push(&r.heap, keyed[T]{key: math.Log(openFloat64(r.src)) / weight, value: v})
+		if len(r.heap) == r.k {
+			r.resetSkip()
+		}
+		return nil
+	}
+
+	r.skip -= weight
+	if r.skip > 0 {
+		return nil
+	}
+	// the new key is drawn from the part of the distribution above the minimum
+	tw := math.Exp(r.heap[0].key * weight)
+	u := tw + (1-tw)*openFloat64(r.src)
+	r.heap[0] = keyed[T]{key: math.Log(u) / weight, value: v}
+	heap.Fix(&r.heap, 0)
+	r.resetSkip()
+	return nil
+}
+
+func (r *WeightedReservoir[T]) resetSkip() {
+	r.skip = math.Log(openFloat64(r.src)) / r.heap[0].key
+}
+
+// Sample returns the current sample in no particular order.
+func (r *WeightedReservoir[T]) Sample() []T {
+	s := make([]T, len(r.heap))
+	for i, it := range r.heap {
+		s[i] = it.value
+	}
+	return s
+}
+
+// keyHeap is a min-heap of keys.
+type keyHeap[T any] []keyed[T]
+
+func (h keyHeap[T]) Len() int           { return len(h) }
+func (h keyHeap[T]) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h keyHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *keyHeap[T]) Push(x any)        { *h = append(*h, x.(keyed[T])) }
+func (h *keyHeap[T]) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
@@ -1,3 +1,4 @@
+// Package searchx searches sorted slices.
 package searchx
 
 import (
@@ -5,18 +6,153 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+func compare[T constraints.Ordered](i, j T) int {
+	if i < j {
+		return -1
+	}
+	if i > j {
+		return 1
+	}
+	return 0
+}
+
 func Search[T constraints.Ordered](s []T, x T) (int, bool) {
-	return SearchCustom(s, x, func(i, j T) int {
-		if i < j {
-			return -1
+	return SearchCustom(s, x, compare[T])
+}
+
+func SearchCustom[T any](s []T, x T, less func(i, j T) int) (int, bool) {
+	return slices.BinarySearchFunc(s, x, less)
+}
+
+// LowerBound returns the index of the first element that is not less than x.
+// If every element is less than x, it returns len(s).
+func LowerBound[T constraints.Ordered](s []T, x T) int {
+	return LowerBoundCustom(s, x, compare[T])
+}
+
+func LowerBoundCustom[T any](s []T, x T, less func(i, j T) int) int {
+	return lowerBound(0, len(s), func(i int) bool { return less(s[i], x) < 0 })
+}
+
+// UpperBound returns the index of the first element that is greater than x.
+// If no element is greater than x, it returns len(s).
+func UpperBound[T constraints.Ordered](s []T, x T) int {
+	return UpperBoundCustom(s, x, compare[T])
+}
+
+func UpperBoundCustom[T any](s []T, x T, less func(i, j T) int) int {
+	return lowerBound(0, len(s), func(i int) bool { return less(s[i], x) <= 0 })
+}
+
+// EqualRange returns the half-open range [lo, hi) of elements equal to x.
+// The range is empty (lo == hi) when x is not present.
+func EqualRange[T constraints.Ordered](s []T, x T) (lo, hi int) {
+	return EqualRangeCustom(s, x, compare[T])
+}
+
+func EqualRangeCustom[T any](s []T, x T, less func(i, j T) int) (lo, hi int) {
+	lo = LowerBoundCustom(s, x, less)
+	hi = lo + UpperBoundCustom(s[lo:], x, less)
+	return lo, hi
+}
+
+// ExponentialSearch finds x by doubling the probe distance from the front
+// before binary searching the bracketed range. It is faster than Search when
+// x is near the beginning of s.
+func ExponentialSearch[T constraints.Ordered](s []T, x T) (int, bool) {
+	return ExponentialSearchCustom(s, x, compare[T])
+}
+
+func ExponentialSearchCustom[T any](s []T, x T, less func(i, j T) int) (int, bool) {
+	return ExponentialSearchFunc(func(i int) (T, bool) {
+		if i >= len(s) {
+			var zero T
+			return zero, false
 		}
-		if i > j {
-			return 1
+		return s[i], true
+	}, x, less)
+}
+
+// ExponentialSearchFunc searches sorted data of unknown length.
+// at(i) returns the i-th element, or false when i is past the end.
+// Only O(log i) indices are probed, so at can be backed by a lazily
+// filled buffer over a stream.
+func ExponentialSearchFunc[T any](at func(i int) (T, bool), x T, less func(i, j T) int) (int, bool) {
+	lo, hi := 0, 1
+	for {
+		v, ok := at(hi - 1)
+		if !ok || less(v, x) >= 0 {
+			break
 		}
-		return 0
+		lo = hi
+		hi <<= 1
+	}
+
+	// the answer is in [lo, hi); elements past the end count as greater than x
+	i := lowerBound(lo, hi, func(i int) bool {
+		v, ok := at(i)
+		return ok && less(v, x) < 0
 	})
+	v, ok := at(i)
+	return i, ok && less(v, x) == 0
 }
 
-func SearchCustom[T any](s []T, x T, less func(i, j T) int) (int, bool) {
-	return slices.BinarySearchFunc(s, x, less)
+// InterpolationSearch estimates the position of x from the values at the
+// ends of the search range. It runs in O(log log n) for evenly spread keys
+// and degrades to O(n) for skewed ones.
+func InterpolationSearch[T constraints.Integer](s []T, x T) (int, bool) {
+	return InterpolationSearchCustom(s, x, compare[T], func(v T) float64 {
+		return float64(v)
+	})
+}
+
+// InterpolationSearchCustom is InterpolationSearch for any element type.
+// key maps an element to a number that grows with its order in less.
+func InterpolationSearchCustom[T any](s []T, x T, less func(i, j T) int, key func(T) float64) (int, bool) {
+	lo, hi := 0, len(s)-1
+	kx := key(x)
+	for lo <= hi {
+		if less(x, s[lo]) < 0 {
+			return lo, false
+		}
+		if less(x, s[hi]) > 0 {
+			return hi + 1, false
+		}
+
+		klo, khi := key(s[lo]), key(s[hi])
+		mid := lo
+		if khi > klo {
+			mid = lo + int(float64(hi-lo)*((kx-klo)/(khi-klo)))
+			if mid < lo {
+				mid = lo
+			} else if mid > hi {
+				mid = hi
+			}
+		}
+
+		switch c := less(s[mid], x); {
+		case c < 0:
+			lo = mid + 1
+		case c > 0:
+			hi = mid - 1
+		default:
+			// step back to the first of equal elements, like Search
+			return LowerBoundCustom(s[lo:mid+1], x, less) + lo, true
+		}
+	}
+	return lo, false
+}
+
+// lowerBound returns the smallest index i in [lo, hi) for which before(i) is
+// false, or hi if there is none. before must be monotonic.
+func lowerBound(lo, hi int, before func(i int) bool) int {
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if before(mid) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
 }
@@ -0,0 +1,83 @@
+package searchx_test
+
+import (
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/searchx"
+)
+
+func TestBounds(t *testing.T) {
+	s := []int{1, 2, 2, 2, 5, 7, 7, 9}
+	for _, test := range []struct {
+		x      int
+		lo, hi int
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{2, 1, 4},
+		{3, 4, 4},
+		{7, 5, 7},
+		{9, 7, 8},
+		{10, 8, 8},
+	} {
+		if i := searchx.LowerBound(s, test.x); i != test.lo {
+			t.Errorf("LowerBound(%d) = %d, want %d", test.x, i, test.lo)
+		}
+		if i := searchx.UpperBound(s, test.x); i != test.hi {
+			t.Errorf("UpperBound(%d) = %d, want %d", test.x, i, test.hi)
+		}
+		if lo, hi := searchx.EqualRange(s, test.x); lo != test.lo || hi != test.hi {
+			t.Errorf("EqualRange(%d) = [%d, %d), want [%d, %d)", test.x, lo, hi, test.lo, test.hi)
+		}
+	}
+}
+
+func TestSearchVariants(t *testing.T) {
+	s := make([]int, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		s = append(s, i*3+(i%3))
+	}
+
+	for x := -5; x < 3100; x++ {
+		want, wantOk := searchx.Search(s, x)
+
+		if i, ok := searchx.ExponentialSearch(s, x); i != want || ok != wantOk {
+			t.Fatalf("ExponentialSearch(%d) = %d, %v, want %d, %v", x, i, ok, want, wantOk)
+		}
+		if i, ok := searchx.InterpolationSearch(s, x); i != want || ok != wantOk {
+			t.Fatalf("InterpolationSearch(%d) = %d, %v, want %d, %v", x, i, ok, want, wantOk)
+		}
+	}
+}
+
+func TestInterpolationSearchDuplicates(t *testing.T) {
+	s := []uint8{3, 3, 3, 3, 3, 3, 3}
+	if i, ok := searchx.InterpolationSearch(s, 3); i != 0 || !ok {
+		t.Errorf("InterpolationSearch(3) = %d, %v, want 0, true", i, ok)
+	}
+	if i, ok := searchx.InterpolationSearch(s, 4); i != len(s) || ok {
+		t.Errorf("InterpolationSearch(4) = %d, %v, want %d, false", i, ok, len(s))
+	}
+	if i, ok := searchx.InterpolationSearch([]uint8{}, 4); i != 0 || ok {
+		t.Errorf("InterpolationSearch on empty = %d, %v, want 0, false", i, ok)
+	}
+}
+
+func TestExponentialSearchFunc(t *testing.T) {
+	var probes int
+	at := func(i int) (int, bool) {
+		probes++
+		return i * 2, true // unbounded even numbers
+	}
+	less := func(i, j int) int { return i - j }
+
+	if i, ok := searchx.ExponentialSearchFunc(at, 1000000, less); i != 500000 || !ok {
+		t.Fatalf("ExponentialSearchFunc(1000000) = %d, %v", i, ok)
+	}
+	if i, ok := searchx.ExponentialSearchFunc(at, 7, less); i != 4 || ok {
+		t.Fatalf("ExponentialSearchFunc(7) = %d, %v", i, ok)
+	}
+	if probes > 100 {
+		t.Fatalf("too many probes: %d", probes)
+	}
+}
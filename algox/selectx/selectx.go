@@ -0,0 +1,149 @@
+// Package selectx selects the smallest elements of a slice or stream without
+// sorting all of it.
+package selectx
+
+import (
+	"math/bits"
+
+	"github.com/unsafe-risk/utilx/algox/sortx"
+	"golang.org/x/exp/constraints"
+)
+
+// NthElement reorders s so that s[n] holds the element that would be there if
+// s were sorted, every element before it is not greater and every element
+// after it is not less. It runs in O(len(s)) on average.
+func NthElement[T constraints.Ordered](s []T, n int) {
+	NthElementCustom(s, n, func(i, j T) bool {
+		return i < j
+	})
+}
+
+// NthElementCustom is an introselect: quickselect that falls back to sorting
+// the remaining range when partitioning goes badly, bounding the worst case
+// to O(n log n). It panics if n is out of range.
+func NthElementCustom[T any](s []T, n int, less func(i, j T) bool) {
+	if n < 0 || n >= len(s) {
+		panic("selectx: index out of range")
+	}
+
+	lo, hi := 0, len(s)
+	limit := 2 * bits.Len(uint(len(s)))
+	for hi-lo > 16 {
+		if limit == 0 {
+			sortx.SortCustom(s[lo:hi], less)
+			return
+		}
+		limit--
+
+		p := partition(s[lo:hi], less) + lo
+		switch {
+		case n < p:
+			hi = p
+		case n > p:
+			lo = p + 1
+		default:
+			return
+		}
+	}
+	sortx.SortCustom(s[lo:hi], less)
+}
+
+// PartialSort reorders s so that s[:k] holds the k smallest elements in
+// ascending order. The order of s[k:] is unspecified.
+func PartialSort[T constraints.Ordered](s []T, k int) {
+	PartialSortCustom(s, k, func(i, j T) bool {
+		return i < j
+	})
+}
+
+// PartialSortCustom runs in O(len(s) log k). k is clamped to [0, len(s)].
+func PartialSortCustom[T any](s []T, k int, less func(i, j T) bool) {
+	if k > len(s) {
+		k = len(s)
+	}
+	if k <= 0 {
+		return
+	}
+
+	h := s[:k]
+	heapify(h, less)
+	for i := k; i < len(s); i++ {
+		if less(s[i], h[0]) {
+			s[i], h[0] = h[0], s[i]
+			siftDown(h, 0, less)
+		}
+	}
+	// pop the heap in place: the greatest element moves to the back each time
+	for end := k - 1; end > 0; end-- {
+		h[0], h[end] = h[end], h[0]
+		siftDown(h[:end], 0, less)
+	}
+}
+
+// partition moves a median-of-three pivot to its final place and returns its
+// index. Elements before it are not greater, elements after it not less.
+func partition[T any](s []T, less func(i, j T) bool) int {
+	mid, last := len(s)/2, len(s)-1
+	if less(s[mid], s[0]) {
+		s[mid], s[0] = s[0], s[mid]
+	}
+	if less(s[last], s[0]) {
+		s[last], s[0] = s[0], s[last]
+	}
+	if less(s[last], s[mid]) {
+		s[last], s[mid] = s[mid], s[last]
+	}
+	// s[0] <= s[mid] <= s[last]; park the pivot at last-1
+	s[mid], s[last-1] = s[last-1], s[mid]
+	pivot := s[last-1]
+
+	i, j := 0, last-1
+	for {
+		for i++; less(s[i], pivot); i++ {
+		}
+		for j--; less(pivot, s[j]); j-- {
+		}
+		if i >= j {
+			break
+		}
+		s[i], s[j] = s[j], s[i]
+	}
+	s[i], s[last-1] = s[last-1], s[i]
+	return i
+}
+
+// heap helpers for a max-heap under less
+
+func heapify[T any](h []T, less func(i, j T) bool) {
+	for i := len(h)/2 - 1; i >= 0; i-- {
+		siftDown(h, i, less)
+	}
+}
+
+func siftDown[T any](h []T, i int, less func(i, j T) bool) {
+	for {
+		c := 2*i + 1
+		if c >= len(h) {
+			return
+		}
+		if c+1 < len(h) && less(h[c], h[c+1]) {
+			c++
+		}
+		if !less(h[i], h[c]) {
+			return
+		}
+		h[i], h[c] = h[c], h[i]
+		i = c
+	}
+}
+
+func siftUp[T any](h []T, i int, less func(i, j T) bool) {
+	for i > 0 {
+		p := (i - 1) / 2
+		if !less(h[p], h[i]) {
+			return
+		}
+		h[p], h[i] = h[i], h[p]
+		i = p
+	}
+}
@@ -0,0 +1,105 @@
+package selectx_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/selectx"
+	"github.com/unsafe-risk/utilx/algox/sortx"
+	"golang.org/x/exp/slices"
+)
+
+func randInts(r *rand.Rand, n, max int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = r.Intn(max)
+	}
+	return s
+}
+
+func TestNthElement(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 10, 17, 100, 1000} {
+		for _, max := range []int{3, 1 << 30} {
+			s := randInts(r, n, max)
+			sorted := append([]int(nil), s...)
+			sortx.Sort(sorted, false)
+
+			for _, k := range []int{0, n / 3, n / 2, n - 1} {
+				selectx.NthElement(s, k)
+				if s[k] != sorted[k] {
+					t.Fatalf("NthElement(n=%d, k=%d) = %d, want %d", n, k, s[k], sorted[k])
+				}
+				for i := 0; i < k; i++ {
+					if s[i] > s[k] {
+						t.Fatalf("NthElement: s[%d] = %d > s[%d] = %d", i, s[i], k, s[k])
+					}
+				}
+				for i := k + 1; i < n; i++ {
+					if s[i] < s[k] {
+						t.Fatalf("NthElement: s[%d] = %d < s[%d] = %d", i, s[i], k, s[k])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNthElementSorted(t *testing.T) {
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = i
+	}
+	selectx.NthElement(s, 5000)
+	if s[5000] != 5000 {
+		t.Fatalf("NthElement on sorted input = %d", s[5000])
+	}
+}
+
+func TestPartialSort(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	s := randInts(r, 1000, 500)
+	sorted := append([]int(nil), s...)
+	sortx.Sort(sorted, false)
+
+	for _, k := range []int{0, 1, 10, 999, 1000, 2000} {
+		c := append([]int(nil), s...)
+		selectx.PartialSort(c, k)
+		if k > len(c) {
+			k = len(c)
+		}
+		if !slices.Equal(c[:k], sorted[:k]) {
+			t.Fatalf("PartialSort(k=%d) = %v", k, c[:k])
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	s := randInts(r, 10000, 1<<20)
+	greater := func(i, j int) bool { return i > j }
+
+	top := selectx.NewTopK(100, greater)
+	if _, ok := top.Threshold(); ok {
+		t.Fatal("Threshold is set before k items were pushed")
+	}
+	for _, v := range s {
+		top.Push(v)
+	}
+
+	sortx.SortReverse(s)
+	if !slices.Equal(top.Items(), s[:100]) {
+		t.Fatal("TopK.Items returned wrong items")
+	}
+	if v, ok := top.Threshold(); !ok || v != s[99] {
+		t.Fatalf("Threshold = %d, %v, want %d", v, ok, s[99])
+	}
+	if top.Push(s[len(s)-1]) {
+		t.Fatal("TopK kept an item below the threshold")
+	}
+
+	empty := selectx.NewTopK(0, greater)
+	if empty.Push(1) || empty.Len() != 0 {
+		t.Fatal("TopK(0) kept an item")
+	}
+}
@@ -0,0 +1,67 @@
+package selectx
+
+import "github.com/unsafe-risk/utilx/algox/sortx"
+
+// TopK keeps the k elements that come first under less out of a stream of
+// items, using O(k) memory and O(log k) time per item. To keep the k highest
+// scores, order by descending score:
+//
+//	top := selectx.NewTopK(100, func(i, j Item) bool { return i.Score > j.Score })
+//
+// TopK is not safe for concurrent use.
+type TopK[T any] struct {
+	k    int
+	less func(i, j T) bool
+	// max-heap under less: the root is the element that would be evicted next
+	heap []T
+}
+
+func NewTopK[T any](k int, less func(i, j T) bool) *TopK[T] {
+	if k < 0 {
+		k = 0
+	}
+	return &TopK[T]{
+		k:    k,
+		less: less,
+		heap: make([]T, 0, k),
+	}
+}
+
+// Push offers v and reports whether it was kept.
+func (t *TopK[T]) Push(v T) bool {
+	if len(t.heap) < t.k {
+		t.heap = append(t.heap, v)
+		siftUp(t.heap, len(t.heap)-1, t.less)
+		return true
+	}
+	if t.k == 0 || !t.less(v, t.heap[0]) {
+		return false
+	}
+	t.heap[0] = v
+	siftDown(t.heap, 0, t.less)
+	return true
+}
+
+// Threshold returns the element that a new item has to beat, once k items
+// have been kept.
+func (t *TopK[T]) Threshold() (v T, ok bool) {
+	if t.k == 0 || len(t.heap) < t.k {
+		return
+	}
+	return t.heap[0], true
+}
+
+func (t *TopK[T]) Len() int {
+	return len(t.heap)
+}
+
+// Items returns the kept elements sorted by less. It does not reset t.
+func (t *TopK[T]) Items() []T {
+	items := append([]T(nil), t.heap...)
+	sortx.SortCustom(items, t.less)
+	return items
+}
+
+func (t *TopK[T]) Reset() {
+	t.heap = t.heap[:0]
+}
@@ -0,0 +1,23 @@
+package shardx
+
+// Jump maps key to a bucket in [0, buckets) with Lamping and Veach's jump
+// consistent hash. When buckets grows by one, only 1/buckets of the keys move,
+// all of them to the new bucket. It needs no state, but buckets can only be
+// added or removed at the end. It returns -1 if buckets <= 0.
+func Jump(key uint64, buckets int) int {
+	if buckets <= 0 {
+		return -1
+	}
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// JumpString is Jump for a string key hashed with DefaultHash.
+func JumpString(key string, buckets int) int {
+	return Jump(DefaultHash([]byte(key)), buckets)
+}
@@ -0,0 +1,116 @@
+package shardx
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/unsafe-risk/utilx/algox/randx"
+)
+
+// Rendezvous assigns keys with highest random weight (HRW) hashing: every
+// node scores the key and the highest score wins. Removing a node only moves
+// the keys it owned, and no virtual nodes are needed, at the cost of O(nodes)
+// per lookup.
+//
+// Rendezvous is safe for concurrent use.
+type Rendezvous struct {
+	mu    sync.RWMutex
+	hash  HashFunc
+	nodes []hrwNode // sorted by name
+}
+
+type hrwNode struct {
+	name   string
+	seed   uint64
+	weight float64
+}
+
+// NewRendezvous returns an empty hasher. A nil hash means DefaultHash.
+func NewRendezvous(hash HashFunc) *Rendezvous {
+	if hash == nil {
+		hash = DefaultHash
+	}
+	return &Rendezvous{hash: hash}
+}
+
+// Add adds node with the given weight, or changes its weight. Keys are
+// assigned in proportion to the weights. A weight <= 0 removes the node.
+func (r *Rendezvous) Add(node string, weight float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if weight <= 0 {
+		r.remove(node)
+		return
+	}
+	i := sort.Search(len(r.nodes), func(i int) bool { return r.nodes[i].name >= node })
+	if i < len(r.nodes) && r.nodes[i].name == node {
+		r.nodes[i].weight = weight
+		return
+	}
+	r.nodes = append(r.nodes, hrwNode{})
+	copy(r.nodes[i+1:], r.nodes[i:])
+	r.nodes[i] = hrwNode{name: node, seed: r.hash([]byte(node)), weight: weight}
+}
+
+func (r *Rendezvous) Remove(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(node)
+}
+
+func (r *Rendezvous) remove(node string) {
+	i := sort.Search(len(r.nodes), func(i int) bool { return r.nodes[i].name >= node })
+	if i < len(r.nodes) && r.nodes[i].name == node {
+		r.nodes = append(r.nodes[:i], r.nodes[i+1:]...)
+	}
+}
+
+// Get returns the node with the highest score for key. ok is false if there
+// are no nodes.
+func (r *Rendezvous) Get(key string) (node string, ok bool) {
+	nodes := r.GetN(key, 1)
+	if len(nodes) == 0 {
+		return "", false
+	}
+	return nodes[0], true
+}
+
+// GetN returns up to n nodes for key, highest score first.
+func (r *Rendezvous) GetN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n > len(r.nodes) {
+		n = len(r.nodes)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	type scored struct {
+		name  string
+		score float64
+	}
+	kh := r.hash([]byte(key))
+	scores := make([]scored, len(r.nodes))
+	for i, node := range r.nodes {
+		scores[i] = scored{node.name, score(randx.Mix64(kh^node.seed), node.weight)}
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].score > scores[j].score
+	})
+
+	nodes := make([]string, n)
+	for i := range nodes {
+		nodes[i] = scores[i].name
+	}
+	return nodes
+}
+
+// score is the logarithmic method of weighted rendezvous hashing:
+// -weight / ln(u) for u uniform in (0, 1).
+func score(h uint64, weight float64) float64 {
+	u := (float64(h>>11) + 0.5) / (1 << 53)
+	return -weight / math.Log(u)
+}
@@ -0,0 +1,152 @@
+package shardx
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultReplicas is the number of virtual nodes of a node with weight 1.
+const DefaultReplicas = 160
+
+type vnode struct {
+	hash uint64
+	node string
+}
+
+// Ring is a consistent hash ring. Each node owns replicas*weight points on the
+// ring, and a key belongs to the node owning the first point at or after the
+// hash of the key. Adding or removing a node only moves the keys of the points
+// it owns.
+//
+// Ring is safe for concurrent use.
+type Ring struct {
+	mu       sync.RWMutex
+	hash     HashFunc
+	replicas int
+	weights  map[string]int
+	points   []vnode // sorted by hash, then node
+}
+
+// NewRing returns an empty ring. replicas <= 0 means DefaultReplicas and a nil
+// hash means DefaultHash.
+func NewRing(replicas int, hash HashFunc) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+	if hash == nil {
+		hash = DefaultHash
+	}
+	return &Ring{
+		hash:     hash,
+		replicas: replicas,
+		weights:  make(map[string]int),
+	}
+}
+
+// Add adds node with the given weight, or changes its weight. A node with
+// weight 2 receives about twice the keys of a node with weight 1.
+// A weight <= 0 removes the node.
+func (r *Ring) Add(node string, weight int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if weight <= 0 {
+		r.remove(node)
+		return
+	}
+	if r.weights[node] == weight {
+		return
+	}
+	r.remove(node)
+	r.weights[node] = weight
+
+	buf := make([]byte, 0, len(node)+12)
+	for i := 0; i < r.replicas*weight; i++ {
+		buf = append(buf[:0], node...)
+		buf = append(buf, '#')
+		buf = strconv.AppendInt(buf, int64(i), 10)
+		r.points = append(r.points, vnode{hash: r.hash(buf), node: node})
+	}
+	sort.Slice(r.points, func(i, j int) bool {
+		if r.points[i].hash != r.points[j].hash {
+			return r.points[i].hash < r.points[j].hash
+		}
+		return r.points[i].node < r.points[j].node
+	})
+}
+
+func (r *Ring) Remove(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(node)
+}
+
+func (r *Ring) remove(node string) {
+	if _, ok := r.weights[node]; !ok {
+		return
+	}
+	delete(r.weights, node)
+	n := 0
+	for _, p := range r.points {
+		if p.node != node {
+			r.points[n] = p
+			n++
+		}
+	}
+	r.points = r.points[:n]
+}
+
+// Nodes returns the nodes of the ring in no particular order.
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := make([]string, 0, len(r.weights))
+	for node := range r.weights {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// Get returns the node owning key. ok is false if the ring is empty.
+func (r *Ring) Get(key string) (node string, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 {
+		return "", false
+	}
+	return r.points[r.search(key)].node, true
+}
+
+// GetN returns up to n distinct nodes for key, walking the ring clockwise
+// from its owner. It suits picking replicas of a key.
+func (r *Ring) GetN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n > len(r.weights) {
+		n = len(r.weights)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	nodes := make([]string, 0, n)
+	seen := make(map[string]bool, n)
+	for i, start := 0, r.search(key); len(nodes) < n; i++ {
+		p := r.points[(start+i)%len(r.points)]
+		if !seen[p.node] {
+			seen[p.node] = true
+			nodes = append(nodes, p.node)
+		}
+	}
+	return nodes
+}
+
+func (r *Ring) search(key string) int {
+	h := r.hash([]byte(key))
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return i
+}
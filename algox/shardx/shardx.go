@@ -0,0 +1,14 @@
+// Package shardx maps keys to shards with consistent, rendezvous and jump
+// hashing.
+package shardx
+
+import "github.com/unsafe-risk/utilx/algox/hashx"
+
+// HashFunc hashes a key. It must give the same result in every process, so
+// randomly seeded hashes such as hash/maphash are not suitable.
+type HashFunc func(key []byte) uint64
+
+// DefaultHash is xxHash64 with a zero seed.
+func DefaultHash(key []byte) uint64 {
+	return hashx.XXHash64(key, 0)
+}
@@ -0,0 +1,175 @@
+package shardx_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/shardx"
+)
+
+const numKeys = 20000
+
+func key(i int) string {
+	return fmt.Sprintf("key-%d", i)
+}
+
+func TestJump(t *testing.T) {
+	counts := make([]int, 10)
+	for i := 0; i < numKeys; i++ {
+		h := shardx.DefaultHash([]byte(key(i)))
+		b10, b11 := shardx.Jump(h, 10), shardx.Jump(h, 11)
+		if b10 != b11 && b11 != 10 {
+			t.Fatalf("key moved from %d to %d instead of the new bucket", b10, b11)
+		}
+		counts[b10]++
+	}
+	for b, c := range counts {
+		if math.Abs(float64(c)-numKeys/10) > numKeys/10*0.1 {
+			t.Errorf("bucket %d has %d keys", b, c)
+		}
+	}
+	if shardx.Jump(1, 0) != -1 {
+		t.Error("Jump with no buckets should be -1")
+	}
+	if shardx.JumpString("a", 1) != 0 {
+		t.Error("Jump with one bucket should be 0")
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := shardx.NewRing(0, nil)
+	if _, ok := r.Get("x"); ok {
+		t.Fatal("Get on empty ring succeeded")
+	}
+	r.Add("a", 1)
+	r.Add("b", 1)
+	r.Add("c", 2)
+
+	before := make(map[string]string)
+	counts := make(map[string]int)
+	for i := 0; i < numKeys; i++ {
+		n, _ := r.Get(key(i))
+		before[key(i)] = n
+		counts[n]++
+	}
+	// c has half the total weight
+	if c := float64(counts["c"]) / numKeys; c < 0.4 || c > 0.6 {
+		t.Errorf("weighted node owns %.2f of the keys", c)
+	}
+
+	// a ring built in another order assigns the same way
+	r2 := shardx.NewRing(0, nil)
+	r2.Add("c", 2)
+	r2.Add("b", 1)
+	r2.Add("a", 1)
+	for k, n := range before {
+		if n2, _ := r2.Get(k); n2 != n {
+			t.Fatalf("rings disagree on %s: %s vs %s", k, n, n2)
+		}
+	}
+
+	r.Remove("b")
+	for k, n := range before {
+		n2, _ := r.Get(k)
+		if n != "b" && n2 != n {
+			t.Fatalf("%s moved from %s to %s", k, n, n2)
+		}
+		if n2 == "b" {
+			t.Fatalf("%s still maps to the removed node", k)
+		}
+	}
+
+	if nodes := r.GetN("x", 5); len(nodes) != 2 || nodes[0] == nodes[1] {
+		t.Fatalf("GetN = %v", nodes)
+	}
+}
+
+func TestRendezvous(t *testing.T) {
+	r := shardx.NewRendezvous(nil)
+	if _, ok := r.Get("x"); ok {
+		t.Fatal("Get with no nodes succeeded")
+	}
+	r.Add("a", 1)
+	r.Add("b", 1)
+	r.Add("c", 2)
+
+	before := make(map[string]string)
+	counts := make(map[string]int)
+	for i := 0; i < numKeys; i++ {
+		n, _ := r.Get(key(i))
+		before[key(i)] = n
+		counts[n]++
+	}
+	if c := float64(counts["c"]) / numKeys; c < 0.45 || c > 0.55 {
+		t.Errorf("weighted node owns %.2f of the keys", c)
+	}
+
+	r.Remove("a")
+	for k, n := range before {
+		n2, _ := r.Get(k)
+		if n != "a" && n2 != n {
+			t.Fatalf("%s moved from %s to %s", k, n, n2)
+		}
+	}
+
+	nodes := r.GetN("x", 3)
+	if len(nodes) != 2 {
+		t.Fatalf("GetN = %v", nodes)
+	}
+	if first, _ := r.Get("x"); nodes[0] != first {
+		t.Fatalf("GetN()[0] = %s, Get = %s", nodes[0], first)
+	}
+}
+
+// TestGolden pins the assignments, which must not change between processes
+// or releases: a change here moves keys between shards.
+func TestGolden(t *testing.T) {
+	if h := shardx.DefaultHash([]byte("a")); h != 0xd24ec4f1a98c6e5b {
+		t.Errorf("DefaultHash(a) = %#x", h)
+	}
+	for _, test := range []struct {
+		key         uint64
+		in1000, in7 int
+	}{
+		{0, 0, 0},
+		{1, 549, 6},
+		{0xdeadbeef, 285, 5},
+		{1 << 63, 453, 5},
+	} {
+		if b := shardx.Jump(test.key, 1000); b != test.in1000 {
+			t.Errorf("Jump(%#x, 1000) = %d, want %d", test.key, b, test.in1000)
+		}
+		if b := shardx.Jump(test.key, 7); b != test.in7 {
+			t.Errorf("Jump(%#x, 7) = %d, want %d", test.key, b, test.in7)
+		}
+	}
+
+	ring := shardx.NewRing(0, nil)
+	hrw := shardx.NewRendezvous(nil)
+	for node, weight := range map[string]int{"a": 1, "b": 1, "c": 2} {
+		ring.Add(node, weight)
+		hrw.Add(node, float64(weight))
+	}
+	for key, want := range map[string]struct {
+		ring, rendezvous string
+		jump             int
+	}{
+		"alice": {"b", "c", 1},
+		"bob":   {"b", "a", 2},
+		"carol": {"c", "c", 1},
+		"dave":  {"a", "c", 6},
+		"eve":   {"b", "c", 2},
+		"frank": {"a", "b", 4},
+	} {
+		if n, _ := ring.Get(key); n != want.ring {
+			t.Errorf("Ring.Get(%q) = %s, want %s", key, n, want.ring)
+		}
+		if n, _ := hrw.Get(key); n != want.rendezvous {
+			t.Errorf("Rendezvous.Get(%q) = %s, want %s", key, n, want.rendezvous)
+		}
+		if b := shardx.JumpString(key, 10); b != want.jump {
+			t.Errorf("JumpString(%q, 10) = %d, want %d", key, b, want.jump)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package sortx
+
+import "golang.org/x/exp/constraints"
+
+// Comparator orders two values: negative if i comes before j, positive if
+// after, zero if they are equal. It has the shape of searchx.SearchCustom's
+// comparator, and its Less method fits SortCustom, SortStableCustom and
+// IsSortedCustom.
+//
+//	byAge := sortx.By(func(p Person) int { return p.Age })
+//	byName := sortx.By(func(p Person) string { return p.Name })
+//	sortx.SortStableCustom(people, byAge.Desc().ThenBy(byName).Less)
+type Comparator[T any] func(i, j T) int
+
+// By orders values by the key extracted with key, ascending.
+func By[T any, K constraints.Ordered](key func(T) K) Comparator[T] {
+	return func(i, j T) int {
+		ki, kj := key(i), key(j)
+		if ki < kj {
+			return -1
+		}
+		if ki > kj {
+			return 1
+		}
+		return 0
+	}
+}
+
+// ByLess builds a Comparator from a less function.
+func ByLess[T any](less func(i, j T) bool) Comparator[T] {
+	return func(i, j T) int {
+		if less(i, j) {
+			return -1
+		}
+		if less(j, i) {
+			return 1
+		}
+		return 0
+	}
+}
+
+// ThenBy breaks ties of c with next.
+func (c Comparator[T]) ThenBy(next Comparator[T]) Comparator[T] {
+	return func(i, j T) int {
+		if r := c(i, j); r != 0 {
+			return r
+		}
+		return next(i, j)
+	}
+}
+
+// Desc reverses the order of c, including every comparator chained into it.
+func (c Comparator[T]) Desc() Comparator[T] {
+	return func(i, j T) int {
+		return c(j, i)
+	}
+}
+
+func (c Comparator[T]) Less(i, j T) bool {
+	return c(i, j) < 0
+}
+
+type keyed[T, K any] struct {
+	key   K
+	value T
+}
+
+// SortBy sorts s by the key computed with key. Each key is computed once and
+// cached, so it suits keys that are expensive to derive.
+func SortBy[T any, K constraints.Ordered](s []T, key func(T) K) {
+	SortByCustom(s, key, func(i, j K) bool {
+		return i < j
+	})
+}
+
+func SortStableBy[T any, K constraints.Ordered](s []T, key func(T) K) {
+	SortStableByCustom(s, key, func(i, j K) bool {
+		return i < j
+	})
+}
+
+func IsSortedBy[T any, K constraints.Ordered](s []T, key func(T) K) bool {
+	if len(s) < 2 {
+		return true
+	}
+	prev := key(s[0])
+	for _, v := range s[1:] {
+		k := key(v)
+		if k < prev {
+			return false
+		}
+		prev = k
+	}
+	return true
+}
+
+func SortByCustom[T, K any](s []T, key func(T) K, less func(i, j K) bool) {
+	sortBy(s, key, less, SortCustom[keyed[T, K]])
+}
+
+func SortStableByCustom[T, K any](s []T, key func(T) K, less func(i, j K) bool) {
+	sortBy(s, key, less, SortStableCustom[keyed[T, K]])
+}
+
+func sortBy[T, K any](s []T, key func(T) K, less func(i, j K) bool, sort func([]keyed[T, K], func(i, j keyed[T, K]) bool)) {
+	ks := make([]keyed[T, K], len(s))
+	for i, v := range s {
+		ks[i] = keyed[T, K]{key: key(v), value: v}
+	}
+	sort(ks, func(i, j keyed[T, K]) bool {
+		return less(i.key, j.key)
+	})
+	for i := range ks {
+		s[i] = ks[i].value
+	}
+}
@@ -0,0 +1,88 @@
+package sortx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/searchx"
+	"github.com/unsafe-risk/utilx/algox/sortx"
+	"golang.org/x/exp/slices"
+)
+
+type person struct {
+	name string
+	age  int
+}
+
+func TestComparator(t *testing.T) {
+	people := []person{
+		{"carol", 30},
+		{"alice", 25},
+		{"bob", 30},
+		{"dave", 25},
+		{"erin", 35},
+	}
+	byAge := sortx.By(func(p person) int { return p.age })
+	byName := sortx.By(func(p person) string { return p.name })
+
+	cmp := byAge.Desc().ThenBy(byName)
+	sortx.SortStableCustom(people, cmp.Less)
+	want := []person{
+		{"erin", 35},
+		{"bob", 30},
+		{"carol", 30},
+		{"alice", 25},
+		{"dave", 25},
+	}
+	if !slices.Equal(people, want) {
+		t.Fatalf("SortStableCustom = %v, want %v", people, want)
+	}
+	if !sortx.IsSortedCustom(people, cmp.Less) {
+		t.Fatal("IsSortedCustom = false")
+	}
+	if sortx.IsSortedCustom(people, byAge.ThenBy(byName).Less) {
+		t.Fatal("IsSortedCustom = true for the reverse order")
+	}
+
+	if i, ok := searchx.SearchCustom(people, person{"carol", 30}, cmp); i != 2 || !ok {
+		t.Fatalf("SearchCustom = %d, %v", i, ok)
+	}
+
+	byLess := sortx.ByLess(func(i, j person) bool { return i.name < j.name })
+	if byLess(people[0], people[1]) != 1 || byLess(people[1], people[1]) != 0 {
+		t.Fatal("ByLess returned wrong order")
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	var calls int
+	key := func(s string) string {
+		calls++
+		return strings.ToLower(s)
+	}
+
+	s := []string{"b", "C", "a", "B", "A"}
+	sortx.SortStableBy(s, key)
+	if !slices.Equal(s, []string{"a", "A", "b", "B", "C"}) {
+		t.Fatalf("SortStableBy = %v", s)
+	}
+	if calls != len(s) {
+		t.Fatalf("key called %d times, want %d", calls, len(s))
+	}
+	if !sortx.IsSortedBy(s, key) {
+		t.Fatal("IsSortedBy = false")
+	}
+
+	n := []int{3, -4, 1, -2}
+	sortx.SortByCustom(n, func(v int) int {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}, func(i, j int) bool {
+		return i > j
+	})
+	if !slices.Equal(n, []int{-4, 3, -2, 1}) {
+		t.Fatalf("SortByCustom = %v", n)
+	}
+}
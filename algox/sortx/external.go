@@ -0,0 +1,314 @@
+package sortx
+
+import (
+	"bufio"
+	"container/heap"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// Codec reads and writes records of an external sort.
+type Codec[T any] interface {
+	// Encode writes one record.
+	Encode(w *bufio.Writer, v T) error
+	// Decode reads one record. It returns io.EOF when there are no more records.
+	Decode(r *bufio.Reader) (T, error)
+	// Size estimates the memory held by v, in bytes.
+	Size(v T) int
+}
+
+// LineCodec is a Codec for newline separated text records.
+type LineCodec struct{}
+
+func (LineCodec) Encode(w *bufio.Writer, v string) error {
+	if _, err := w.WriteString(v); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+func (LineCodec) Decode(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF && len(line) > 0 {
+		// last line without a trailing newline
+		return line, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+func (LineCodec) Size(v string) int {
+	return len(v) + 16
+}
+
+const (
+	DefaultExternalMaxMemory = 64 << 20
+	DefaultExternalFanIn     = 64
+)
+
+var ErrInvalidExternalConfig = errors.New("invalid external sort config")
+
+// ExternalConfig controls the resources used by ExternalSortCustom.
+// The zero value uses the defaults.
+type ExternalConfig struct {
+	// MaxMemory caps the estimated size of records held in memory at once.
+	MaxMemory int
+	// TempDir is where sorted runs are spilled. Empty means os.TempDir().
+	TempDir string
+	// FanIn is the maximum number of runs merged at once, which bounds the
+	// number of open temp files.
+	FanIn int
+}
+
+// ExternalSortCustom sorts the records of src into dst using bounded memory.
+// Records are read with codec, sorted in chunks of at most config.MaxMemory
+// bytes, spilled to temp files as sorted runs and k-way merged into dst.
+// Like SortCustom, the order of equal records is not preserved.
+func ExternalSortCustom[T any](dst io.Writer, src io.Reader, codec Codec[T], less func(i, j T) bool, config *ExternalConfig) error {
+	var c ExternalConfig
+	if config != nil {
+		c = *config
+	}
+	if c.MaxMemory == 0 {
+		c.MaxMemory = DefaultExternalMaxMemory
+	}
+	if c.FanIn == 0 {
+		c.FanIn = DefaultExternalFanIn
+	}
+	if c.MaxMemory < 0 || c.FanIn < 2 {
+		return ErrInvalidExternalConfig
+	}
+
+	e := &external[T]{codec: codec, less: less, config: c}
+	defer e.cleanup()
+
+	r := bufio.NewReader(src)
+	w := bufio.NewWriter(dst)
+	var chunk []T
+	for {
+		chunk = chunk[:0]
+		eof, err := e.readChunk(r, &chunk)
+		if err != nil {
+			return err
+		}
+		SortCustom(chunk, less)
+
+		if eof && e.runs == nil {
+			// everything fit in memory
+			if err := e.writeAll(w, chunk); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+		if len(chunk) > 0 {
+			if err := e.spill(chunk); err != nil {
+				return err
+			}
+		}
+		if eof {
+			break
+		}
+	}
+	chunk = nil
+
+	for len(e.runs) > c.FanIn {
+		if err := e.mergePass(); err != nil {
+			return err
+		}
+	}
+	if err := e.merge(w, e.runs); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+type external[T any] struct {
+	codec  Codec[T]
+	less   func(i, j T) bool
+	config ExternalConfig
+	dir    string
+	runs   []string
+}
+
+func (e *external[T]) readChunk(r *bufio.Reader, chunk *[]T) (eof bool, err error) {
+	var size int
+	for size < e.config.MaxMemory || len(*chunk) == 0 {
+		v, err := e.codec.Decode(r)
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		*chunk = append(*chunk, v)
+		size += e.codec.Size(v)
+	}
+	return false, nil
+}
+
+func (e *external[T]) writeAll(w *bufio.Writer, s []T) error {
+	for _, v := range s {
+		if err := e.codec.Encode(w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *external[T]) newRun() (*os.File, error) {
+	if e.dir == "" {
+		dir, err := os.MkdirTemp(e.config.TempDir, "sortx-")
+		if err != nil {
+			return nil, err
+		}
+		e.dir = dir
+	}
+	f, err := os.CreateTemp(e.dir, "run-")
+	if err != nil {
+		return nil, err
+	}
+	e.runs = append(e.runs, f.Name())
+	return f, nil
+}
+
+func (e *external[T]) spill(chunk []T) error {
+	f, err := e.newRun()
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := e.writeAll(w, chunk); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// mergePass merges runs in groups of FanIn until fewer runs remain.
+func (e *external[T]) mergePass() error {
+	runs := e.runs
+	e.runs = nil
+	for len(runs) > 0 {
+		n := e.config.FanIn
+		if n > len(runs) {
+			n = len(runs)
+		}
+		group := runs[:n]
+		runs = runs[n:]
+
+		f, err := e.newRun()
+		if err != nil {
+			return err
+		}
+		w := bufio.NewWriter(f)
+		if err := e.merge(w, group); err != nil {
+			f.Close()
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		for _, name := range group {
+			os.Remove(name)
+		}
+	}
+	return nil
+}
+
+func (e *external[T]) merge(w *bufio.Writer, runs []string) error {
+	h := &runHeap[T]{less: e.less}
+	defer func() {
+		for _, c := range h.cursors {
+			c.f.Close()
+		}
+	}()
+
+	for _, name := range runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		c := &runCursor[T]{f: f, r: bufio.NewReader(f)}
+		ok, err := c.next(e.codec)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if !ok {
+			f.Close()
+			continue
+		}
+		h.cursors = append(h.cursors, c)
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		c := h.cursors[0]
+		if err := e.codec.Encode(w, c.head); err != nil {
+			return err
+		}
+		ok, err := c.next(e.codec)
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+			c.f.Close()
+		}
+	}
+	return nil
+}
+
+func (e *external[T]) cleanup() {
+	if e.dir != "" {
+		os.RemoveAll(e.dir)
+	}
+}
+
+type runCursor[T any] struct {
+	f    *os.File
+	r    *bufio.Reader
+	head T
+}
+
+func (c *runCursor[T]) next(codec Codec[T]) (bool, error) {
+	v, err := codec.Decode(c.r)
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	c.head = v
+	return true, nil
+}
+
+type runHeap[T any] struct {
+	cursors []*runCursor[T]
+	less    func(i, j T) bool
+}
+
+func (h *runHeap[T]) Len() int           { return len(h.cursors) }
+func (h *runHeap[T]) Less(i, j int) bool { return h.less(h.cursors[i].head, h.cursors[j].head) }
+func (h *runHeap[T]) Swap(i, j int)      { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+func (h *runHeap[T]) Push(x any)         { h.cursors = append(h.cursors, x.(*runCursor[T])) }
+func (h *runHeap[T]) Pop() any {
+	n := len(h.cursors) - 1
+	c := h.cursors[n]
+	h.cursors = h.cursors[:n]
+	return c
+}
@@ -0,0 +1,67 @@
+package sortx_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/sortx"
+	"golang.org/x/exp/slices"
+)
+
+func TestExternalSort(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	lines := make([]string, 10000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%08x", r.Uint32())
+	}
+	want := append([]string(nil), lines...)
+	slices.Sort(want)
+
+	less := func(i, j string) bool { return i < j }
+	for _, config := range []*sortx.ExternalConfig{
+		nil,
+		{MaxMemory: 4096},
+		{MaxMemory: 1024, FanIn: 3},
+	} {
+		dir := t.TempDir()
+		if config != nil {
+			config.TempDir = dir
+		}
+
+		var out bytes.Buffer
+		src := strings.NewReader(strings.Join(lines, "\n"))
+		if err := sortx.ExternalSortCustom[string](&out, src, sortx.LineCodec{}, less, config); err != nil {
+			t.Fatal(err)
+		}
+
+		got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if !slices.Equal(got, want) {
+			t.Fatalf("ExternalSortCustom(%+v) returned unsorted output", config)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Fatalf("temp files were left behind: %v", entries)
+		}
+	}
+}
+
+func TestExternalSortEmpty(t *testing.T) {
+	var out bytes.Buffer
+	err := sortx.ExternalSortCustom[string](&out, strings.NewReader(""), sortx.LineCodec{}, func(i, j string) bool {
+		return i < j
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
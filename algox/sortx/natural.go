@@ -0,0 +1,121 @@
+package sortx
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SortNatural sorts strings so that runs of ASCII digits are ordered by their
+// numeric value: "node2" comes before "node10".
+func SortNatural(s []string) {
+	SortCustom(s, LessNatural)
+}
+
+func LessNatural(a, b string) bool {
+	return CompareNatural(a, b) < 0
+}
+
+// CompareNatural compares a and b treating runs of ASCII digits as numbers.
+// Numbers that only differ by leading zeros are ordered shortest first, so
+// the result is 0 only when a == b.
+func CompareNatural(a, b string) int {
+	var zeros int
+	for len(a) > 0 && len(b) > 0 {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			var na, nb string
+			na, a = digitRun(a)
+			nb, b = digitRun(b)
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return compareInt(len(ta), len(tb))
+			}
+			if r := strings.Compare(ta, tb); r != 0 {
+				return r
+			}
+			if zeros == 0 {
+				zeros = compareInt(len(na), len(nb))
+			}
+			continue
+		}
+
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if (ra == utf8.RuneError && sa == 1) || (rb == utf8.RuneError && sb == 1) {
+			// invalid UTF-8 compares by bytes, which orders valid runes
+			// the same way as their code points
+			if a[0] != b[0] {
+				return compareInt(int(a[0]), int(b[0]))
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if ra != rb {
+			return compareInt(int(ra), int(rb))
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	if len(a) != len(b) {
+		return compareInt(len(a), len(b))
+	}
+	return zeros
+}
+
+// CompareFold compares a and b ignoring case, using full Unicode case folding
+// rather than the rules of any locale. Canonically equivalent strings, such as
+// precomposed and combining accents, compare equal.
+func CompareFold(a, b string) int {
+	return strings.Compare(foldKey(a), foldKey(b))
+}
+
+func LessFold(a, b string) bool {
+	return CompareFold(a, b) < 0
+}
+
+// CompareNaturalFold is CompareNatural applied after the folding of CompareFold.
+func CompareNaturalFold(a, b string) int {
+	return CompareNatural(foldKey(a), foldKey(b))
+}
+
+func LessNaturalFold(a, b string) bool {
+	return CompareNaturalFold(a, b) < 0
+}
+
+// CompareNormalized compares the NFC forms of a and b, so canonically
+// equivalent strings compare equal.
+func CompareNormalized(a, b string) int {
+	return strings.Compare(norm.NFC.String(a), norm.NFC.String(b))
+}
+
+func LessNormalized(a, b string) bool {
+	return CompareNormalized(a, b) < 0
+}
+
+// foldKey returns the canonical caseless form of s: NFD(fold(NFD(s))).
+func foldKey(s string) string {
+	return norm.NFD.String(cases.Fold().String(norm.NFD.String(s)))
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func digitRun(s string) (run, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
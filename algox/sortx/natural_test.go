@@ -0,0 +1,76 @@
+package sortx_test
+
+import (
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/searchx"
+	"github.com/unsafe-risk/utilx/algox/sortx"
+	"golang.org/x/exp/slices"
+)
+
+func TestSortNatural(t *testing.T) {
+	s := []string{"node10", "node2", "node1", "node", "node02", "node2a", "Node3", "x9", "x10y", "10", "9"}
+	sortx.SortNatural(s)
+	want := []string{"9", "10", "Node3", "node", "node1", "node2", "node02", "node2a", "node10", "x9", "x10y"}
+	if !slices.Equal(s, want) {
+		t.Fatalf("SortNatural = %q, want %q", s, want)
+	}
+
+	if i, ok := searchx.SearchCustom(s, "node10", sortx.CompareNatural); i != 8 || !ok {
+		t.Fatalf("SearchCustom(node10) = %d, %v", i, ok)
+	}
+}
+
+func TestCompareNatural(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"a", "a", 0},
+		{"a2", "a10", -1},
+		{"a10", "a2", 1},
+		{"a1", "a01", -1},
+		{"a01b", "a1c", -1},
+		{"18446744073709551616", "18446744073709551615", 1},
+		{"v1.2.10", "v1.2.9", 1},
+		{"가2", "가10", -1},
+		{"\xff", "\xfe", 1},
+		{"a1\xfe", "a1\xff", -1},
+		{"\xff", "\ufffd", 1},
+		{"\xc3z", "é", -1},
+	} {
+		if r := sortx.CompareNatural(test.a, test.b); r != test.want {
+			t.Errorf("CompareNatural(%q, %q) = %d, want %d", test.a, test.b, r, test.want)
+		}
+	}
+}
+
+func TestCompareFold(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{"Hello", "hELLO", 0},
+		{"Straße", "STRASSE", 0},
+		{"é", "É", 0},
+		{"apple", "Banana", -1},
+	} {
+		if r := sortx.CompareFold(test.a, test.b); r != test.want {
+			t.Errorf("CompareFold(%q, %q) = %d, want %d", test.a, test.b, r, test.want)
+		}
+	}
+
+	if sortx.CompareNormalized("é", "é") != 0 {
+		t.Error("CompareNormalized: NFC and NFD forms differ")
+	}
+	if sortx.CompareNormalized("é", "É") == 0 {
+		t.Error("CompareNormalized: case was folded")
+	}
+
+	s := []string{"File10", "file2", "FILE1"}
+	sortx.SortCustom(s, sortx.LessNaturalFold)
+	if !slices.Equal(s, []string{"FILE1", "file2", "File10"}) {
+		t.Fatalf("SortCustom(LessNaturalFold) = %q", s)
+	}
+}
@@ -0,0 +1,120 @@
+package sortx
+
+import (
+	"runtime"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+const (
+	// below this length a chunk is sorted on the calling goroutine
+	parallelThreshold = 1 << 12
+	// runs of this length are insertion sorted before merging
+	insertionRun = 24
+)
+
+// ParallelSort sorts s with a merge sort that uses up to workers goroutines.
+// If workers <= 0, runtime.GOMAXPROCS(0) is used.
+func ParallelSort[T constraints.Ordered](s []T, workers int) {
+	ParallelSortCustom(s, func(i, j T) bool {
+		return i < j
+	}, workers)
+}
+
+func ParallelSortReverse[T constraints.Ordered](s []T, workers int) {
+	ParallelSortCustom(s, func(i, j T) bool {
+		return i > j
+	}, workers)
+}
+
+// ParallelSortCustom is a stable parallel merge sort.
+// It allocates one buffer of len(s) elements.
+func ParallelSortCustom[T any](s []T, less func(i, j T) bool, workers int) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if len(s) < 2 {
+		return
+	}
+	parallelMergeSort(s, make([]T, len(s)), less, workers)
+}
+
+func parallelMergeSort[T any](s, buf []T, less func(i, j T) bool, workers int) {
+	if workers <= 1 || len(s) < parallelThreshold {
+		mergeSort(s, buf, less)
+		return
+	}
+
+	mid := len(s) / 2
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		parallelMergeSort(s[:mid], buf[:mid], less, workers/2)
+	}()
+	parallelMergeSort(s[mid:], buf[mid:], less, workers-workers/2)
+	wg.Wait()
+
+	// halves are already in order
+	if !less(s[mid], s[mid-1]) {
+		return
+	}
+	merge(s[:mid], s[mid:], buf, less)
+	copy(s, buf)
+}
+
+// merge writes the stable merge of a and b into dst.
+func merge[T any](a, b, dst []T, less func(i, j T) bool) {
+	var i, j, k int
+	for i < len(a) && j < len(b) {
+		if less(b[j], a[i]) {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// mergeSort is a sequential bottom-up stable merge sort using buf as scratch.
+func mergeSort[T any](s, buf []T, less func(i, j T) bool) {
+	for lo := 0; lo < len(s); lo += insertionRun {
+		hi := lo + insertionRun
+		if hi > len(s) {
+			hi = len(s)
+		}
+		insertionSort(s[lo:hi], less)
+	}
+
+	src, dst := s, buf[:len(s)]
+	for width := insertionRun; width < len(s); width *= 2 {
+		for lo := 0; lo < len(s); lo += 2 * width {
+			mid, hi := lo+width, lo+2*width
+			if mid > len(s) {
+				mid = len(s)
+			}
+			if hi > len(s) {
+				hi = len(s)
+			}
+			merge(src[lo:mid], src[mid:hi], dst[lo:hi], less)
+		}
+		src, dst = dst, src
+	}
+
+	if len(s) > 0 && &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+func insertionSort[T any](s []T, less func(i, j T) bool) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && less(s[j], s[j-1]); j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
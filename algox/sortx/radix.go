@@ -0,0 +1,107 @@
+package sortx
+
+import (
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// RadixSort sorts integers in ascending order with an LSD radix sort.
+// It runs in O(n * sizeof(T)) and allocates one buffer of len(s) elements.
+func RadixSort[T constraints.Integer](s []T) {
+	if len(s) < 2 {
+		return
+	}
+
+	var zero T
+	width := uint(unsafe.Sizeof(zero))
+	// flip the sign bit so negative values come first
+	var flip uint64
+	if zero-1 < zero {
+		flip = 1 << (width*8 - 1)
+	}
+
+	src, dst := s, make([]T, len(s))
+	for shift := uint(0); shift < width*8; shift += 8 {
+		var count [256]int
+		for _, v := range src {
+			count[byte((uint64(v)^flip)>>shift)]++
+		}
+		if skipPass(&count, len(src)) {
+			continue
+		}
+		prefixSum(count[:])
+		for _, v := range src {
+			b := byte((uint64(v) ^ flip) >> shift)
+			dst[count[b]] = v
+			count[b]++
+		}
+		src, dst = dst, src
+	}
+
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+// RadixSortBytes sorts byte keys in lexicographic order with an LSD radix sort.
+// It is meant for fixed-width keys such as hashes or encoded IDs; shorter keys
+// are ordered before longer keys that they prefix, like bytes.Compare.
+func RadixSortBytes(s [][]byte) {
+	if len(s) < 2 {
+		return
+	}
+
+	var width int
+	for _, v := range s {
+		if len(v) > width {
+			width = len(v)
+		}
+	}
+
+	src, dst := s, make([][]byte, len(s))
+	for pos := width - 1; pos >= 0; pos-- {
+		// bucket 0 holds keys that end before pos
+		var count [257]int
+		for _, v := range src {
+			count[bytesBucket(v, pos)]++
+		}
+		prefixSum(count[:])
+		for _, v := range src {
+			b := bytesBucket(v, pos)
+			dst[count[b]] = v
+			count[b]++
+		}
+		src, dst = dst, src
+	}
+
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+func bytesBucket(v []byte, pos int) int {
+	if pos >= len(v) {
+		return 0
+	}
+	return int(v[pos]) + 1
+}
+
+// skipPass reports whether every element falls in the same bucket.
+func skipPass(count *[256]int, n int) bool {
+	for _, c := range count {
+		if c != 0 {
+			return c == n
+		}
+	}
+	return true
+}
+
+// prefixSum turns bucket counts into bucket start offsets.
+func prefixSum(count []int) {
+	var sum int
+	for i, c := range count {
+		count[i] = sum
+		sum += c
+	}
+}
@@ -1,3 +1,4 @@
+// Package sortx sorts slices and streams.
 package sortx
 
 import (
@@ -6,6 +7,10 @@ import (
 )
 
 func Sort[T constraints.Ordered](s []T, reverse bool) {
+	if reverse {
+		SortReverse(s)
+		return
+	}
 	SortCustom(s, func(i, j T) bool {
 		return i < j
 	})
@@ -0,0 +1,151 @@
+package sortx_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/algox/sortx"
+	"golang.org/x/exp/slices"
+)
+
+func randInt64s(n int, seed int64) []int64 {
+	r := rand.New(rand.NewSource(seed))
+	s := make([]int64, n)
+	for i := range s {
+		s[i] = r.Int63() - r.Int63()
+	}
+	return s
+}
+
+func TestSortReverse(t *testing.T) {
+	s := []int{3, 1, 2}
+	sortx.Sort(s, true)
+	if !slices.Equal(s, []int{3, 2, 1}) {
+		t.Fatalf("Sort(reverse) = %v", s)
+	}
+	sortx.Sort(s, false)
+	if !slices.Equal(s, []int{1, 2, 3}) {
+		t.Fatalf("Sort = %v", s)
+	}
+}
+
+func TestParallelSort(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000, 100000} {
+		for _, workers := range []int{0, 1, 3, 8} {
+			s := randInt64s(n, int64(n))
+			sortx.ParallelSort(s, workers)
+			if !sortx.IsSorted(s) {
+				t.Fatalf("ParallelSort(n=%d, workers=%d) is not sorted", n, workers)
+			}
+		}
+	}
+}
+
+func TestParallelSortStable(t *testing.T) {
+	type pair struct{ key, idx int }
+	s := make([]pair, 50000)
+	r := rand.New(rand.NewSource(1))
+	for i := range s {
+		s[i] = pair{r.Intn(100), i}
+	}
+	sortx.ParallelSortCustom(s, func(i, j pair) bool {
+		return i.key < j.key
+	}, 4)
+	for i := 1; i < len(s); i++ {
+		if s[i-1].key == s[i].key && s[i-1].idx > s[i].idx {
+			t.Fatalf("ParallelSortCustom is not stable at %d", i)
+		}
+	}
+}
+
+func TestRadixSort(t *testing.T) {
+	s := randInt64s(10000, 2)
+	sortx.RadixSort(s)
+	if !sortx.IsSorted(s) {
+		t.Fatal("RadixSort[int64] is not sorted")
+	}
+
+	i8 := []int8{5, -1, 127, -128, 0, -7, 3}
+	sortx.RadixSort(i8)
+	if !slices.Equal(i8, []int8{-128, -7, -1, 0, 3, 5, 127}) {
+		t.Fatalf("RadixSort[int8] = %v", i8)
+	}
+
+	u := []uint64{1 << 63, 42, 0, 1<<64 - 1, 7}
+	sortx.RadixSort(u)
+	if !slices.Equal(u, []uint64{0, 7, 42, 1 << 63, 1<<64 - 1}) {
+		t.Fatalf("RadixSort[uint64] = %v", u)
+	}
+}
+
+func TestRadixSortBytes(t *testing.T) {
+	s := [][]byte{
+		[]byte("banana"),
+		[]byte("apple"),
+		[]byte("app"),
+		[]byte(""),
+		[]byte("cherry"),
+		[]byte("apple"),
+		{0xff, 0x00},
+	}
+	want := make([][]byte, len(s))
+	copy(want, s)
+	slices.SortFunc(want, func(i, j []byte) bool {
+		return bytes.Compare(i, j) < 0
+	})
+
+	sortx.RadixSortBytes(s)
+	for i := range s {
+		if !bytes.Equal(s[i], want[i]) {
+			t.Fatalf("RadixSortBytes = %q, want %q", s, want)
+		}
+	}
+}
+
+const benchSize = 1 << 20
+
+func BenchmarkSort(b *testing.B) {
+	src := randInt64s(benchSize, 3)
+	s := make([]int64, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(s, src)
+		sortx.Sort(s, false)
+	}
+}
+
+func BenchmarkParallelSort(b *testing.B) {
+	src := randInt64s(benchSize, 3)
+	s := make([]int64, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(s, src)
+		sortx.ParallelSort(s, 0)
+	}
+}
+
+func BenchmarkRadixSort(b *testing.B) {
+	src := randInt64s(benchSize, 3)
+	s := make([]int64, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(s, src)
+		sortx.RadixSort(s)
+	}
+}
+
+func BenchmarkRadixSortBytes(b *testing.B) {
+	r := rand.New(rand.NewSource(4))
+	src := make([][]byte, benchSize/4)
+	for i := range src {
+		src[i] = make([]byte, 16)
+		r.Read(src[i])
+	}
+	s := make([][]byte, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(s, src)
+		sortx.RadixSortBytes(s)
+	}
+}
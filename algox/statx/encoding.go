@@ -0,0 +1,88 @@
+package statx
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var ErrInvalidEncoding = errors.New("invalid encoding of a statistic")
+
+// The first byte of every binary encoding identifies the type and the version
+// of its format.
+const (
+	summaryFormat byte = iota + 1
+	tdigestFormat
+	ewmaFormat
+	timeEWMAFormat
+)
+
+func appendFloat(b []byte, f float64) []byte {
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func newDecoder(b []byte, format byte) *decoder {
+	if len(b) == 0 || b[0] != format {
+		return &decoder{err: ErrInvalidEncoding}
+	}
+	return &decoder{b: b[1:]}
+}
+
+func (d *decoder) float() float64 {
+	if d.err != nil || len(d.b) < 8 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+	f := math.Float64frombits(binary.LittleEndian.Uint64(d.b))
+	d.b = d.b[8:]
+	return f
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil || len(d.b) < 1 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+	c := d.b[0]
+	d.b = d.b[1:]
+	return c
+}
+
+// done returns the first error, or ErrInvalidEncoding if bytes are left over.
+func (d *decoder) done() error {
+	if d.err == nil && len(d.b) > 0 {
+		return ErrInvalidEncoding
+	}
+	return d.err
+}
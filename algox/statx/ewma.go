@@ -0,0 +1,217 @@
+package statx
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// EWMA is an exponentially weighted moving average over values added at a
+// regular pace: each new value x moves the average by alpha*(x-average).
+// The first value initializes the average.
+//
+// An EWMA is not safe for concurrent use.
+type EWMA struct {
+	alpha float64
+	value float64
+	init  bool
+}
+
+// NewEWMA returns an EWMA with a smoothing factor in (0, 1]. It panics if
+// alpha is out of range.
+func NewEWMA(alpha float64) *EWMA {
+	if !(alpha > 0 && alpha <= 1) {
+		panic("statx: EWMA alpha must be in (0, 1]")
+	}
+	return &EWMA{alpha: alpha}
+}
+
+// NewEWMASpan returns an EWMA whose average is comparable to a simple moving
+// average over the last n values: alpha = 2/(n+1).
+func NewEWMASpan(n int) *EWMA {
+	if n < 1 {
+		panic("statx: EWMA span must be at least 1")
+	}
+	return NewEWMA(2 / (float64(n) + 1))
+}
+
+func (e *EWMA) Add(x float64) {
+	if !e.init {
+		e.value, e.init = x, true
+		return
+	}
+	e.value += e.alpha * (x - e.value)
+}
+
+// Value returns the average, or NaN if no value was added.
+func (e *EWMA) Value() float64 {
+	if !e.init {
+		return math.NaN()
+	}
+	return e.value
+}
+
+func (e *EWMA) Reset() {
+	e.value, e.init = 0, false
+}
+
+func (e *EWMA) MarshalBinary() ([]byte, error) {
+	b := appendFloat([]byte{ewmaFormat}, e.alpha)
+	b = appendFloat(b, e.value)
+	return append(b, boolByte(e.init)), nil
+}
+
+func (e *EWMA) UnmarshalBinary(b []byte) error {
+	d := newDecoder(b, ewmaFormat)
+	v := EWMA{alpha: d.float(), value: d.float(), init: d.byte() == 1}
+	if err := d.done(); err != nil {
+		return err
+	}
+	if !(v.alpha > 0 && v.alpha <= 1) {
+		return ErrInvalidEncoding
+	}
+	*e = v
+	return nil
+}
+
+type ewmaJSON struct {
+	Alpha float64  `json:"alpha"`
+	Value *float64 `json:"value"`
+}
+
+func (e *EWMA) MarshalJSON() ([]byte, error) {
+	v := ewmaJSON{Alpha: e.alpha}
+	if e.init {
+		v.Value = &e.value
+	}
+	return json.Marshal(v)
+}
+
+func (e *EWMA) UnmarshalJSON(b []byte) error {
+	var v ewmaJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if !(v.Alpha > 0 && v.Alpha <= 1) {
+		return ErrInvalidEncoding
+	}
+	*e = EWMA{alpha: v.Alpha}
+	if v.Value != nil {
+		e.value, e.init = *v.Value, true
+	}
+	return nil
+}
+
+// TimeEWMA is an exponentially weighted moving average over values added at
+// irregular times. The weight of a value halves every half-life, so the
+// average does not depend on how often values are added.
+//
+// A TimeEWMA is not safe for concurrent use.
+type TimeEWMA struct {
+	halfLife time.Duration
+	value    float64
+	last     time.Time
+	init     bool
+}
+
+// NewTimeEWMA returns a TimeEWMA with the given half-life. It panics if
+// halfLife <= 0.
+func NewTimeEWMA(halfLife time.Duration) *TimeEWMA {
+	if halfLife <= 0 {
+		panic("statx: TimeEWMA half-life must be positive")
+	}
+	return &TimeEWMA{halfLife: halfLife}
+}
+
+// AddAt adds x observed at t. A value observed at or before the time of the
+// latest one gets a weight of zero and is ignored, except as the first value.
+func (e *TimeEWMA) AddAt(x float64, t time.Time) {
+	if !e.init {
+		e.value, e.last, e.init = x, t, true
+		return
+	}
+	var dt time.Duration
+	if t.After(e.last) {
+		dt, e.last = t.Sub(e.last), t
+	}
+	alpha := -math.Expm1(-math.Ln2 * float64(dt) / float64(e.halfLife))
+	e.value += alpha * (x - e.value)
+}
+
+// Value returns the average, or NaN if no value was added.
+func (e *TimeEWMA) Value() float64 {
+	if !e.init {
+		return math.NaN()
+	}
+	return e.value
+}
+
+// Last returns the time of the latest value.
+func (e *TimeEWMA) Last() time.Time {
+	return e.last
+}
+
+func (e *TimeEWMA) Reset() {
+	e.value, e.last, e.init = 0, time.Time{}, false
+}
+
+func (e *TimeEWMA) MarshalBinary() ([]byte, error) {
+	b := binary.AppendVarint([]byte{timeEWMAFormat}, int64(e.halfLife))
+	b = appendFloat(b, e.value)
+	b = append(b, boolByte(e.init))
+	return binary.AppendVarint(b, e.last.UnixNano()), nil
+}
+
+func (e *TimeEWMA) UnmarshalBinary(b []byte) error {
+	d := newDecoder(b, timeEWMAFormat)
+	v := TimeEWMA{halfLife: time.Duration(d.varint()), value: d.float(), init: d.byte() == 1}
+	last := d.varint()
+	if err := d.done(); err != nil {
+		return err
+	}
+	if v.halfLife <= 0 {
+		return ErrInvalidEncoding
+	}
+	if v.init {
+		v.last = time.Unix(0, last)
+	}
+	*e = v
+	return nil
+}
+
+type timeEWMAJSON struct {
+	HalfLife time.Duration `json:"half_life"`
+	Value    *float64      `json:"value"`
+	Last     time.Time     `json:"last"`
+}
+
+func (e *TimeEWMA) MarshalJSON() ([]byte, error) {
+	v := timeEWMAJSON{HalfLife: e.halfLife, Last: e.last}
+	if e.init {
+		v.Value = &e.value
+	}
+	return json.Marshal(v)
+}
+
+func (e *TimeEWMA) UnmarshalJSON(b []byte) error {
+	var v timeEWMAJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.HalfLife <= 0 {
+		return ErrInvalidEncoding
+	}
+	*e = TimeEWMA{halfLife: v.HalfLife}
+	if v.Value != nil {
+		e.value, e.last, e.init = *v.Value, v.Last, true
+	}
+	return nil
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
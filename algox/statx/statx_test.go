@@ -0,0 +1,274 @@
+package statx_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/unsafe-risk/utilx/algox/randx"
+	"github.com/unsafe-risk/utilx/algox/statx"
+	"github.com/unsafe-risk/utilx/timex/diffx"
+)
+
+func near(a, b, eps float64) bool {
+	return math.Abs(a-b) <= eps
+}
+
+func values(n int, seed uint64) []float64 {
+	src := randx.NewSplitMix64(seed)
+	s := make([]float64, n)
+	for i := range s {
+		// skewed like latencies
+		s[i] = math.Exp(randx.Float64(src) * 5)
+	}
+	return s
+}
+
+func TestSummary(t *testing.T) {
+	var s statx.Summary
+	if !math.IsNaN(s.Mean()) || !math.IsNaN(s.Min()) || s.Count() != 0 {
+		t.Fatal("empty summary is not empty")
+	}
+
+	xs := values(10000, 1)
+	var sum, min, max float64 = 0, math.Inf(1), math.Inf(-1)
+	for _, x := range xs {
+		s.Add(x)
+		sum += x
+		min = math.Min(min, x)
+		max = math.Max(max, x)
+	}
+	mean := sum / float64(len(xs))
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+
+	if s.Count() != uint64(len(xs)) || !near(s.Mean(), mean, 1e-9) || !near(s.Sum(), sum, 1e-6) {
+		t.Errorf("count %d, mean %v, sum %v; want %d, %v, %v", s.Count(), s.Mean(), s.Sum(), len(xs), mean, sum)
+	}
+	if !near(s.Variance(), ss/float64(len(xs)), 1e-6) || !near(s.SampleVariance(), ss/float64(len(xs)-1), 1e-6) {
+		t.Errorf("variance %v, sample variance %v", s.Variance(), s.SampleVariance())
+	}
+	if s.Min() != min || s.Max() != max {
+		t.Errorf("min %v, max %v; want %v, %v", s.Min(), s.Max(), min, max)
+	}
+
+	var a, b statx.Summary
+	for i, x := range xs {
+		if i%3 == 0 {
+			a.Add(x)
+		} else {
+			b.Add(x)
+		}
+	}
+	a.Merge(&b)
+	if a.Count() != s.Count() || !near(a.Mean(), s.Mean(), 1e-9) || !near(a.Variance(), s.Variance(), 1e-6) ||
+		a.Min() != s.Min() || a.Max() != s.Max() {
+		t.Errorf("merged summary differs: %+v, want %+v", a, s)
+	}
+}
+
+func TestTDigest(t *testing.T) {
+	xs := values(100000, 2)
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	exact := func(q float64) float64 {
+		return sorted[int(q*float64(len(sorted)-1))]
+	}
+
+	// each goroutine fills its own digest, then they are merged
+	parts := make([]*statx.TDigest, 4)
+	var wg sync.WaitGroup
+	for p := range parts {
+		parts[p] = statx.NewTDigest(0)
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := p; i < len(xs); i += len(parts) {
+				parts[p].Add(xs[i])
+			}
+		}(p)
+	}
+	wg.Wait()
+	d := statx.NewTDigest(0)
+	for _, p := range parts {
+		d.Merge(p)
+	}
+
+	if d.Count() != float64(len(xs)) || d.Min() != sorted[0] || d.Max() != sorted[len(sorted)-1] {
+		t.Fatalf("count %v, min %v, max %v", d.Count(), d.Min(), d.Max())
+	}
+	for _, q := range []float64{0.001, 0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+		got := d.Quantile(q)
+		// compare ranks, which is what the t-digest bounds
+		rank := float64(sort.SearchFloat64s(sorted, got)) / float64(len(sorted))
+		if !near(rank, q, 0.01*math.Max(0.1, math.Sqrt(q*(1-q)))) {
+			t.Errorf("Quantile(%v) = %v (rank %v), exact %v", q, got, rank, exact(q))
+		}
+		if cdf := d.CDF(exact(q)); !near(cdf, q, 0.005) {
+			t.Errorf("CDF(%v) = %v, want %v", exact(q), cdf, q)
+		}
+	}
+	if d.Quantile(0) != d.Min() || d.Quantile(1) != d.Max() {
+		t.Error("extreme quantiles are not min and max")
+	}
+
+	empty := statx.NewTDigest(0)
+	if !math.IsNaN(empty.Quantile(0.5)) || !math.IsNaN(empty.CDF(1)) {
+		t.Error("empty digest has quantiles")
+	}
+	one := statx.NewTDigest(0)
+	one.Add(3)
+	if one.Quantile(0.5) != 3 {
+		t.Errorf("single value: got %v", one.Quantile(0.5))
+	}
+}
+
+func TestEWMA(t *testing.T) {
+	e := statx.NewEWMA(0.5)
+	if !math.IsNaN(e.Value()) {
+		t.Fatal("empty EWMA has a value")
+	}
+	for _, x := range []float64{10, 20, 20} {
+		e.Add(x)
+	}
+	if e.Value() != 17.5 {
+		t.Errorf("got %v, want 17.5", e.Value())
+	}
+
+	te := statx.NewTimeEWMA(time.Second)
+	start := time.Unix(1000, 0)
+	te.AddAt(0, start)
+	te.AddAt(100, start.Add(time.Second))
+	if !near(te.Value(), 50, 1e-9) {
+		t.Errorf("after one half-life got %v, want 50", te.Value())
+	}
+	te.AddAt(0, start.Add(time.Second))
+	if te.Value() != 50 {
+		t.Errorf("value at the same time moved the average to %v", te.Value())
+	}
+	te.AddAt(0, start)
+	if te.Value() != 50 || !te.Last().Equal(start.Add(time.Second)) {
+		t.Errorf("earlier value moved the average to %v at %v", te.Value(), te.Last())
+	}
+}
+
+type codec interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	json.Marshaler
+	json.Unmarshaler
+}
+
+func TestEncoding(t *testing.T) {
+	var s statx.Summary
+	d := statx.NewTDigest(50)
+	e := statx.NewEWMA(0.1)
+	te := statx.NewTimeEWMA(time.Minute)
+	for i, x := range values(1000, 3) {
+		s.Add(x)
+		d.Add(x)
+		e.Add(x)
+		te.AddAt(x, time.Unix(int64(i), 0))
+	}
+
+	for _, c := range []struct {
+		v     codec
+		fresh func() codec
+		text  func(codec) string
+	}{
+		{&s, func() codec { return new(statx.Summary) }, func(c codec) string {
+			s := c.(*statx.Summary)
+			return fmt.Sprint(s.Count(), s.Mean(), s.Variance(), s.Min(), s.Max())
+		}},
+		{d, func() codec { return new(statx.TDigest) }, func(c codec) string {
+			d := c.(*statx.TDigest)
+			return fmt.Sprint(d.Count(), d.Quantile(0.1), d.Quantile(0.5), d.Quantile(0.99), d.Min(), d.Max())
+		}},
+		{e, func() codec { return new(statx.EWMA) }, func(c codec) string {
+			return fmt.Sprint(c.(*statx.EWMA).Value())
+		}},
+		{te, func() codec { return new(statx.TimeEWMA) }, func(c codec) string {
+			e := c.(*statx.TimeEWMA)
+			return fmt.Sprint(e.Value(), e.Last().Unix())
+		}},
+	} {
+		want := c.text(c.v)
+
+		b, err := c.v.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := c.fresh()
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Fatalf("%T: %v", c.v, err)
+		}
+		if c.text(got) != want {
+			t.Errorf("%T binary round trip: got %s, want %s", c.v, c.text(got), want)
+		}
+		if err := got.UnmarshalBinary(b[:len(b)-1]); !errors.Is(err, statx.ErrInvalidEncoding) {
+			t.Errorf("%T truncated: got %v", c.v, err)
+		}
+
+		j, err := json.Marshal(c.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = c.fresh()
+		if err := json.Unmarshal(j, got); err != nil {
+			t.Fatalf("%T: %v", c.v, err)
+		}
+		if c.text(got) != want {
+			t.Errorf("%T JSON round trip: got %s, want %s", c.v, c.text(got), want)
+		}
+
+		var buf bytes.Buffer
+		got = c.fresh()
+		if err := gob.NewEncoder(&buf).Encode(c.v); err != nil {
+			t.Fatal(err)
+		}
+		if err := gob.NewDecoder(&buf).Decode(got); err != nil {
+			t.Fatal(err)
+		}
+		if c.text(got) != want {
+			t.Errorf("%T gob round trip: got %s, want %s", c.v, c.text(got), want)
+		}
+	}
+
+	var other statx.Summary
+	if err := other.UnmarshalBinary([]byte{99}); !errors.Is(err, statx.ErrInvalidEncoding) {
+		t.Errorf("wrong format: got %v", err)
+	}
+}
+
+func ExampleTDigest() {
+	latencies := statx.NewTDigest(0)
+	var summary statx.Summary
+	for i := 0; i < 3; i++ {
+		differ := diffx.New()
+		differ.Start()
+		// the measured work
+		differ.End()
+		latencies.Add(float64(differ.GetDiff()))
+		summary.Add(float64(differ.GetDiff()))
+	}
+	fmt.Println(summary.Count(), latencies.Quantile(0.99) >= 0)
+	// Output: 3 true
+}
+
+func BenchmarkTDigestAdd(b *testing.B) {
+	xs := values(1<<16, 4)
+	d := statx.NewTDigest(0)
+	for i := 0; i < b.N; i++ {
+		d.Add(xs[i&(len(xs)-1)])
+	}
+}
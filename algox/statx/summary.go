@@ -0,0 +1,158 @@
+// Package statx computes statistics of streams in bounded memory.
+package statx
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+)
+
+// Summary tracks the count, mean, variance, minimum and maximum of a stream of
+// values in constant memory, with Welford's online algorithm. Summaries of
+// separate streams can be combined with Merge, so each goroutine can keep its
+// own and merge them at the end.
+//
+// The zero value is an empty Summary. It is not safe for concurrent use.
+type Summary struct {
+	n    uint64
+	mean float64
+	m2   float64 // sum of squared differences from the mean
+	min  float64
+	max  float64
+}
+
+func (s *Summary) Add(x float64) {
+	s.n++
+	if s.n == 1 {
+		s.min, s.max = x, x
+	} else if x < s.min {
+		s.min = x
+	} else if x > s.max {
+		s.max = x
+	}
+	d := x - s.mean
+	s.mean += d / float64(s.n)
+	s.m2 += d * (x - s.mean)
+}
+
+// Merge adds every value seen by o to s, as if they had been added to s.
+func (s *Summary) Merge(o *Summary) {
+	if o.n == 0 {
+		return
+	}
+	if s.n == 0 {
+		*s = *o
+		return
+	}
+	n := s.n + o.n
+	d := o.mean - s.mean
+	s.mean += d * float64(o.n) / float64(n)
+	s.m2 += o.m2 + d*d*float64(s.n)*float64(o.n)/float64(n)
+	s.n = n
+	if o.min < s.min {
+		s.min = o.min
+	}
+	if o.max > s.max {
+		s.max = o.max
+	}
+}
+
+func (s *Summary) Reset() {
+	*s = Summary{}
+}
+
+func (s *Summary) Count() uint64 {
+	return s.n
+}
+
+// Mean returns the mean, or NaN if s is empty.
+func (s *Summary) Mean() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.mean
+}
+
+func (s *Summary) Sum() float64 {
+	return s.mean * float64(s.n)
+}
+
+// Variance returns the population variance, or NaN if s is empty.
+func (s *Summary) Variance() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.n)
+}
+
+// SampleVariance returns the unbiased sample variance, or NaN if s has fewer
+// than two values.
+func (s *Summary) SampleVariance() float64 {
+	if s.n < 2 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.n-1)
+}
+
+func (s *Summary) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+func (s *Summary) SampleStdDev() float64 {
+	return math.Sqrt(s.SampleVariance())
+}
+
+// Min returns the smallest value, or NaN if s is empty.
+func (s *Summary) Min() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.min
+}
+
+// Max returns the largest value, or NaN if s is empty.
+func (s *Summary) Max() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.max
+}
+
+func (s *Summary) MarshalBinary() ([]byte, error) {
+	b := binary.AppendUvarint([]byte{summaryFormat}, s.n)
+	for _, f := range [...]float64{s.mean, s.m2, s.min, s.max} {
+		b = appendFloat(b, f)
+	}
+	return b, nil
+}
+
+func (s *Summary) UnmarshalBinary(b []byte) error {
+	d := newDecoder(b, summaryFormat)
+	v := Summary{n: d.uvarint(), mean: d.float(), m2: d.float(), min: d.float(), max: d.float()}
+	if err := d.done(); err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+type summaryJSON struct {
+	Count uint64  `json:"count"`
+	Mean  float64 `json:"mean"`
+	M2    float64 `json:"m2"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+func (s *Summary) MarshalJSON() ([]byte, error) {
+	return json.Marshal(summaryJSON{s.n, s.mean, s.m2, s.min, s.max})
+}
+
+func (s *Summary) UnmarshalJSON(b []byte) error {
+	var v summaryJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*s = Summary{v.Count, v.Mean, v.M2, v.Min, v.Max}
+	return nil
+}
@@ -0,0 +1,314 @@
+package statx
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+
+	"github.com/unsafe-risk/utilx/algox/sortx"
+)
+
+const DefaultCompression = 100
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest estimates quantiles of a stream of values in bounded memory with
+// Dunning's merging t-digest. Estimates are most accurate near the tails: the
+// error at quantile q is roughly proportional to q(1-q)/compression. Digests
+// of separate streams can be combined with Merge.
+//
+// A TDigest is not safe for concurrent use.
+type TDigest struct {
+	compression float64
+	centroids   []centroid // sorted by mean
+	buffer      []centroid // not merged yet
+	weight      float64    // of centroids and buffer
+	min, max    float64
+}
+
+// NewTDigest returns an empty digest that keeps O(compression) centroids.
+// A compression <= 0 means DefaultCompression.
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+	return &TDigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+func (t *TDigest) Add(x float64) {
+	t.AddWeighted(x, 1)
+}
+
+// AddWeighted adds x as if it had been added weight times. NaN values and
+// weights <= 0 are ignored.
+func (t *TDigest) AddWeighted(x, weight float64) {
+	if math.IsNaN(x) || !(weight > 0) {
+		return
+	}
+	t.add(centroid{x, weight})
+	if x < t.min {
+		t.min = x
+	}
+	if x > t.max {
+		t.max = x
+	}
+}
+
+func (t *TDigest) add(c centroid) {
+	t.buffer = append(t.buffer, c)
+	t.weight += c.weight
+	if len(t.buffer) >= t.bufferSize() {
+		t.compress()
+	}
+}
+
+func (t *TDigest) bufferSize() int {
+	return int(5*t.compression) + 10
+}
+
+// Merge adds every value seen by o to t. The result is as accurate as if the
+// values had been added to t directly, up to the usual t-digest error.
+func (t *TDigest) Merge(o *TDigest) {
+	if o.weight == 0 {
+		return
+	}
+	for _, c := range o.centroids {
+		t.add(c)
+	}
+	for _, c := range o.buffer {
+		t.add(c)
+	}
+	if o.min < t.min {
+		t.min = o.min
+	}
+	if o.max > t.max {
+		t.max = o.max
+	}
+}
+
+func (t *TDigest) Reset() {
+	*t = TDigest{compression: t.compression, centroids: t.centroids[:0], buffer: t.buffer[:0], min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// Count returns the total weight added.
+func (t *TDigest) Count() float64 {
+	return t.weight
+}
+
+// Min returns the smallest value, or NaN if t is empty.
+func (t *TDigest) Min() float64 {
+	if t.weight == 0 {
+		return math.NaN()
+	}
+	return t.min
+}
+
+// Max returns the largest value, or NaN if t is empty.
+func (t *TDigest) Max() float64 {
+	if t.weight == 0 {
+		return math.NaN()
+	}
+	return t.max
+}
+
+// compress merges the buffer into the centroids, using the k1 scale function
+// to bound the size of each centroid.
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buffer...)
+	t.buffer = t.buffer[:0]
+	sortx.SortCustom(all, func(i, j centroid) bool {
+		return i.mean < j.mean
+	})
+
+	var done float64 // weight of the centroids before cur
+	limit := t.quantileLimit(0)
+	out := all[:1]
+	for _, next := range all[1:] {
+		cur := &out[len(out)-1]
+		if (done+cur.weight+next.weight)/t.weight <= limit {
+			cur.weight += next.weight
+			cur.mean += (next.mean - cur.mean) * next.weight / cur.weight
+			continue
+		}
+		done += cur.weight
+		limit = t.quantileLimit(done / t.weight)
+		out = append(out, next)
+	}
+	t.centroids = out
+}
+
+// quantileLimit returns the largest quantile a centroid starting at q may
+// reach: one unit further on the k1 scale k(q) = δ/2π asin(2q-1).
+func (t *TDigest) quantileLimit(q float64) float64 {
+	k := t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+	k++
+	if k >= t.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/t.compression) + 1) / 2
+}
+
+// Quantile returns an estimate of the value at quantile q in [0, 1], or NaN
+// if t is empty.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.compress()
+	if t.weight == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return t.min
+	}
+	if q >= 1 {
+		return t.max
+	}
+	cs := t.centroids
+	if len(cs) == 1 {
+		return interpolate(t.min, t.max, q)
+	}
+
+	// each centroid is centred on its mean; the tails run to min and max
+	index := q * t.weight
+	first := cs[0]
+	if index < first.weight/2 {
+		return interpolate(t.min, first.mean, index/(first.weight/2))
+	}
+	cum := first.weight / 2
+	for i := 0; i < len(cs)-1; i++ {
+		dw := (cs[i].weight + cs[i+1].weight) / 2
+		if cum+dw > index {
+			return interpolate(cs[i].mean, cs[i+1].mean, (index-cum)/dw)
+		}
+		cum += dw
+	}
+	last := cs[len(cs)-1]
+	return interpolate(last.mean, t.max, math.Min(1, (index-cum)/(last.weight/2)))
+}
+
+// CDF returns an estimate of the fraction of values <= x, or NaN if t is
+// empty.
+func (t *TDigest) CDF(x float64) float64 {
+	t.compress()
+	if t.weight == 0 || math.IsNaN(x) {
+		return math.NaN()
+	}
+	if x < t.min {
+		return 0
+	}
+	if x >= t.max {
+		return 1
+	}
+	cs := t.centroids
+	if len(cs) == 1 || t.max == t.min {
+		return (x - t.min) / (t.max - t.min)
+	}
+
+	first := cs[0]
+	if x < first.mean {
+		return first.weight / 2 * (x - t.min) / (first.mean - t.min) / t.weight
+	}
+	cum := first.weight / 2
+	for i := 0; i < len(cs)-1; i++ {
+		dw := (cs[i].weight + cs[i+1].weight) / 2
+		if x < cs[i+1].mean {
+			if cs[i+1].mean == cs[i].mean {
+				return (cum + dw) / t.weight
+			}
+			return (cum + dw*(x-cs[i].mean)/(cs[i+1].mean-cs[i].mean)) / t.weight
+		}
+		cum += dw
+	}
+	last := cs[len(cs)-1]
+	return (cum + last.weight/2*(x-last.mean)/(t.max-last.mean)) / t.weight
+}
+
+func interpolate(a, b, f float64) float64 {
+	return a + (b-a)*f
+}
+
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+	b := []byte{tdigestFormat}
+	b = appendFloat(b, t.compression)
+	b = appendFloat(b, t.min)
+	b = appendFloat(b, t.max)
+	b = binary.AppendUvarint(b, uint64(len(t.centroids)))
+	for _, c := range t.centroids {
+		b = appendFloat(b, c.mean)
+		b = appendFloat(b, c.weight)
+	}
+	return b, nil
+}
+
+func (t *TDigest) UnmarshalBinary(b []byte) error {
+	d := newDecoder(b, tdigestFormat)
+	compression, min, max := d.float(), d.float(), d.float()
+	n := d.uvarint()
+	if n > uint64(len(d.b)/16) {
+		return ErrInvalidEncoding
+	}
+	cs := make([]centroid, n)
+	for i := range cs {
+		cs[i] = centroid{d.float(), d.float()}
+	}
+	if err := d.done(); err != nil {
+		return err
+	}
+	return t.set(compression, min, max, cs)
+}
+
+type tdigestJSON struct {
+	Compression float64      `json:"compression"`
+	Min         float64      `json:"min"`
+	Max         float64      `json:"max"`
+	Centroids   [][2]float64 `json:"centroids"` // mean, weight
+}
+
+func (t *TDigest) MarshalJSON() ([]byte, error) {
+	t.compress()
+	v := tdigestJSON{Compression: t.compression, Centroids: make([][2]float64, len(t.centroids))}
+	// JSON has no infinities, so an empty digest stores zeros
+	if t.weight > 0 {
+		v.Min, v.Max = t.min, t.max
+	}
+	for i, c := range t.centroids {
+		v.Centroids[i] = [2]float64{c.mean, c.weight}
+	}
+	return json.Marshal(v)
+}
+
+func (t *TDigest) UnmarshalJSON(b []byte) error {
+	var v tdigestJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	cs := make([]centroid, len(v.Centroids))
+	for i, c := range v.Centroids {
+		cs[i] = centroid{c[0], c[1]}
+	}
+	return t.set(v.Compression, v.Min, v.Max, cs)
+}
+
+// set replaces t with decoded state after checking that it is consistent.
+func (t *TDigest) set(compression, min, max float64, cs []centroid) error {
+	if !(compression > 0) {
+		return ErrInvalidEncoding
+	}
+	var weight float64
+	for i, c := range cs {
+		if !(c.weight > 0) || c.mean < min || c.mean > max || (i > 0 && c.mean < cs[i-1].mean) {
+			return ErrInvalidEncoding
+		}
+		weight += c.weight
+	}
+	if weight == 0 {
+		min, max = math.Inf(1), math.Inf(-1)
+	}
+	*t = TDigest{compression: compression, centroids: cs, weight: weight, min: min, max: max}
+	return nil
+}
@@ -0,0 +1,187 @@
+package stringx
+
+import (
+	"io"
+	"sort"
+)
+
+// Match is an occurrence of a pattern of an AhoCorasick automaton.
+type Match struct {
+	// Pattern is the index of the pattern as passed to NewAhoCorasick.
+	Pattern int
+	// Offset is the position of the first byte of the match.
+	Offset int64
+}
+
+type acEdge struct {
+	c    byte
+	next int32
+}
+
+type acNode struct {
+	edges []acEdge // sorted by c
+	fail  int32
+	// dict is the nearest node on the fail chain that ends a pattern, or -1.
+	dict int32
+	// out lists the patterns ending at this node.
+	out []int32
+}
+
+// AhoCorasick finds every occurrence of a set of patterns in a single pass,
+// in O(len(text) + matches) time. It is safe for concurrent use once built.
+type AhoCorasick struct {
+	nodes   []acNode
+	root    [256]int32 // dense transitions of the root
+	lengths []int
+}
+
+func NewAhoCorasick(patterns ...[]byte) *AhoCorasick {
+	a := &AhoCorasick{
+		nodes:   []acNode{{dict: -1}},
+		lengths: make([]int, len(patterns)),
+	}
+
+	for i, p := range patterns {
+		a.lengths[i] = len(p)
+		if len(p) == 0 {
+			continue
+		}
+		var s int32
+		for _, c := range p {
+			next, ok := a.child(s, c)
+			if !ok {
+				next = int32(len(a.nodes))
+				a.nodes = append(a.nodes, acNode{dict: -1})
+				a.addEdge(s, c, next)
+			}
+			s = next
+		}
+		a.nodes[s].out = append(a.nodes[s].out, int32(i))
+	}
+
+	for c := range a.root {
+		a.root[c], _ = a.child(0, byte(c))
+	}
+
+	// breadth first, so fail links of shallower nodes are ready
+	queue := make([]int32, 0, len(a.nodes))
+	for _, e := range a.nodes[0].edges {
+		queue = append(queue, e.next)
+	}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, e := range a.nodes[s].edges {
+			f := a.step(a.nodes[s].fail, e.c)
+			a.nodes[e.next].fail = f
+			if len(a.nodes[f].out) > 0 {
+				a.nodes[e.next].dict = f
+			} else {
+				a.nodes[e.next].dict = a.nodes[f].dict
+			}
+			queue = append(queue, e.next)
+		}
+	}
+	return a
+}
+
+func NewAhoCorasickStrings(patterns ...string) *AhoCorasick {
+	bs := make([][]byte, len(patterns))
+	for i, p := range patterns {
+		bs[i] = []byte(p)
+	}
+	return NewAhoCorasick(bs...)
+}
+
+// FindAll returns every match in s ordered by end position, then by pattern
+// length, longest first.
+func (a *AhoCorasick) FindAll(s []byte) []Match {
+	var matches []Match
+	a.Scan(s, func(m Match) bool {
+		matches = append(matches, m)
+		return true
+	})
+	return matches
+}
+
+// Contains reports whether any pattern occurs in s.
+func (a *AhoCorasick) Contains(s []byte) bool {
+	var found bool
+	a.Scan(s, func(Match) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// Scan calls f for every match in s until f returns false.
+func (a *AhoCorasick) Scan(s []byte, f func(Match) bool) {
+	var state int32
+	a.scan(&state, s, 0, f)
+}
+
+// ScanReader is Scan over a stream. Offsets count bytes from the start of r,
+// and matches spanning read boundaries are found.
+func (a *AhoCorasick) ScanReader(r io.Reader, f func(Match) bool) error {
+	buf := make([]byte, readBufferSize)
+	var state int32
+	var pos int64
+	for {
+		n, err := r.Read(buf)
+		if !a.scan(&state, buf[:n], pos, f) {
+			return nil
+		}
+		pos += int64(n)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (a *AhoCorasick) scan(state *int32, s []byte, pos int64, f func(Match) bool) bool {
+	st := *state
+	for i, c := range s {
+		st = a.step(st, c)
+		end := pos + int64(i) + 1
+		for o := st; o >= 0; o = a.nodes[o].dict {
+			for _, p := range a.nodes[o].out {
+				if !f(Match{Pattern: int(p), Offset: end - int64(a.lengths[p])}) {
+					return false
+				}
+			}
+		}
+	}
+	*state = st
+	return true
+}
+
+func (a *AhoCorasick) step(s int32, c byte) int32 {
+	for s != 0 {
+		if next, ok := a.child(s, c); ok {
+			return next
+		}
+		s = a.nodes[s].fail
+	}
+	return a.root[c]
+}
+
+func (a *AhoCorasick) child(s int32, c byte) (int32, bool) {
+	edges := a.nodes[s].edges
+	i := sort.Search(len(edges), func(i int) bool { return edges[i].c >= c })
+	if i < len(edges) && edges[i].c == c {
+		return edges[i].next, true
+	}
+	return 0, false
+}
+
+func (a *AhoCorasick) addEdge(s int32, c byte, next int32) {
+	edges := a.nodes[s].edges
+	i := sort.Search(len(edges), func(i int) bool { return edges[i].c >= c })
+	edges = append(edges, acEdge{})
+	copy(edges[i+1:], edges[i:])
+	edges[i] = acEdge{c: c, next: next}
+	a.nodes[s].edges = edges
+}
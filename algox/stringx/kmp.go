@@ -0,0 +1,107 @@
+package stringx
+
+import "io"
+
+// KMP finds a single pattern with the Knuth-Morris-Pratt algorithm in
+// O(len(text) + len(pattern)) time.
+type KMP struct {
+	pattern []byte
+	fail    []int
+}
+
+func NewKMP(pattern []byte) *KMP {
+	fail := make([]int, len(pattern))
+	for i, j := 1, 0; i < len(pattern); i++ {
+		for j > 0 && pattern[i] != pattern[j] {
+			j = fail[j-1]
+		}
+		if pattern[i] == pattern[j] {
+			j++
+		}
+		fail[i] = j
+	}
+	return &KMP{
+		pattern: append([]byte(nil), pattern...),
+		fail:    fail,
+	}
+}
+
+func NewKMPString(pattern string) *KMP {
+	return NewKMP([]byte(pattern))
+}
+
+// Index returns the offset of the first match in s, or -1.
+func (k *KMP) Index(s []byte) int {
+	idx := -1
+	k.Scan(s, func(offset int) bool {
+		idx = offset
+		return false
+	})
+	return idx
+}
+
+// FindAll returns the offsets of every match in s, including overlapping ones.
+func (k *KMP) FindAll(s []byte) []int {
+	var offsets []int
+	k.Scan(s, func(offset int) bool {
+		offsets = append(offsets, offset)
+		return true
+	})
+	return offsets
+}
+
+// Scan calls f with the offset of every match in s until f returns false.
+func (k *KMP) Scan(s []byte, f func(offset int) bool) {
+	if len(k.pattern) == 0 {
+		return
+	}
+	var j int
+	for i := range s {
+		j = k.step(j, s[i])
+		if j == len(k.pattern) {
+			if !f(i + 1 - j) {
+				return
+			}
+			j = k.fail[j-1]
+		}
+	}
+}
+
+// ScanReader is Scan over a stream. Offsets count bytes from the start of r.
+func (k *KMP) ScanReader(r io.Reader, f func(offset int64) bool) error {
+	if len(k.pattern) == 0 {
+		return nil
+	}
+	buf := make([]byte, readBufferSize)
+	var j int
+	var pos int64
+	for {
+		n, err := r.Read(buf)
+		for i := 0; i < n; i++ {
+			j = k.step(j, buf[i])
+			if j == len(k.pattern) {
+				if !f(pos + int64(i+1-j)) {
+					return nil
+				}
+				j = k.fail[j-1]
+			}
+		}
+		pos += int64(n)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (k *KMP) step(j int, c byte) int {
+	for j > 0 && c != k.pattern[j] {
+		j = k.fail[j-1]
+	}
+	if c == k.pattern[j] {
+		j++
+	}
+	return j
+}
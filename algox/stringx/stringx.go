@@ -0,0 +1,4 @@
+// Package stringx finds patterns in strings and streams.
+package stringx
+
+const readBufferSize = 32 * 1024
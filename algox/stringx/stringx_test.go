@@ -0,0 +1,120 @@
+package stringx_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/unsafe-risk/utilx/algox/stringx"
+	"golang.org/x/exp/slices"
+)
+
+func naiveFindAll(s, p []byte) []int {
+	var offsets []int
+	for i := 0; i+len(p) <= len(s); i++ {
+		if bytes.Equal(s[i:i+len(p)], p) {
+			offsets = append(offsets, i)
+		}
+	}
+	return offsets
+}
+
+func randText(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = "abc"[r.Intn(3)]
+	}
+	return b
+}
+
+func TestKMP(t *testing.T) {
+	k := stringx.NewKMPString("aba")
+	if got := k.FindAll([]byte("abababa")); !slices.Equal(got, []int{0, 2, 4}) {
+		t.Fatalf("FindAll = %v", got)
+	}
+	if i := k.Index([]byte("xxaba")); i != 2 {
+		t.Fatalf("Index = %d", i)
+	}
+	if i := k.Index([]byte("abb")); i != -1 {
+		t.Fatalf("Index = %d", i)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 100; n++ {
+		text, pattern := randText(r, 500), randText(r, 1+r.Intn(5))
+		want := naiveFindAll(text, pattern)
+		k := stringx.NewKMP(pattern)
+		if got := k.FindAll(text); !slices.Equal(got, want) {
+			t.Fatalf("FindAll(%q) = %v, want %v", pattern, got, want)
+		}
+
+		var got []int
+		err := k.ScanReader(iotest.OneByteReader(bytes.NewReader(text)), func(offset int64) bool {
+			got = append(got, int(offset))
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("ScanReader(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+}
+
+func TestAhoCorasick(t *testing.T) {
+	a := stringx.NewAhoCorasickStrings("he", "she", "his", "hers")
+	want := []stringx.Match{
+		{Pattern: 1, Offset: 1},
+		{Pattern: 0, Offset: 2},
+		{Pattern: 3, Offset: 2},
+	}
+	if got := a.FindAll([]byte("ushers")); !slices.Equal(got, want) {
+		t.Fatalf("FindAll = %v, want %v", got, want)
+	}
+	if !a.Contains([]byte("this")) || a.Contains([]byte("hxs")) {
+		t.Fatal("Contains returned a wrong result")
+	}
+}
+
+func TestAhoCorasickRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for n := 0; n < 50; n++ {
+		patterns := make([][]byte, 1+r.Intn(20))
+		for i := range patterns {
+			patterns[i] = randText(r, 1+r.Intn(6))
+		}
+		text := randText(r, 2000)
+
+		want := make(map[stringx.Match]bool)
+		for i, p := range patterns {
+			for _, o := range naiveFindAll(text, p) {
+				want[stringx.Match{Pattern: i, Offset: int64(o)}] = true
+			}
+		}
+
+		a := stringx.NewAhoCorasick(patterns...)
+		got := a.FindAll(text)
+		if len(got) != len(want) {
+			t.Fatalf("FindAll found %d matches, want %d", len(got), len(want))
+		}
+		for _, m := range got {
+			if !want[m] {
+				t.Fatalf("FindAll reported a wrong match %v", m)
+			}
+		}
+
+		var streamed []stringx.Match
+		err := a.ScanReader(iotest.HalfReader(bytes.NewReader(text)), func(m stringx.Match) bool {
+			streamed = append(streamed, m)
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(streamed, got) {
+			t.Fatal("ScanReader and FindAll disagree")
+		}
+	}
+}
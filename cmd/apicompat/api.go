@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// symbol is an exported element of a package API.
+type symbol struct {
+	kind string // func, method, type, field, interface method, type set, var, const
+	name string
+	// text is the declaration as written, for the report.
+	text string
+	// sig is the declaration without parameter names and with type parameters
+	// numbered, so that renaming them does not count as a change.
+	sig string
+	// tparams is the normalized type parameter list, if any.
+	tparams string
+	// sealed is set on the interface methods of interfaces with unexported
+	// methods, which cannot be implemented outside their package.
+	sealed bool
+	// parent is the key of the type declaring a field or method.
+	parent string
+}
+
+func (s symbol) key() string {
+	return s.kind + " " + s.name
+}
+
+// api maps the import path of each package to its symbols by key.
+type api map[string]map[string]symbol
+
+// loadAPI returns the exported API of the importable packages of the module
+// rooted at root. Commands, internal packages, tests and files excluded by
+// build constraints are skipped.
+func loadAPI(root string) (api, error) {
+	gomod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	modPath := modfile.ModulePath(gomod)
+	if modPath == "" {
+		return nil, fmt.Errorf("%s: no module directive", filepath.Join(root, "go.mod"))
+	}
+	c := newChecker(root, modPath)
+	a := api{}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if rel != "." {
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || name == "internal" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		syms, err := loadPackage(c, path.Join(modPath, filepath.ToSlash(rel)))
+		if err != nil {
+			return err
+		}
+		if syms != nil {
+			a[path.Join(modPath, filepath.ToSlash(rel))] = syms
+		}
+		return nil
+	})
+	return a, err
+}
+
+// loadPackage returns the symbols of the package with the given import path,
+// or nil if there is no importable package.
+func loadPackage(c *checker, importPath string) (map[string]symbol, error) {
+	p, err := c.load(importPath)
+	if err != nil || p.files == nil {
+		return nil, err
+	}
+
+	syms := map[string]symbol{}
+	add := func(s symbol) {
+		syms[s.key()] = s
+	}
+	for _, file := range p.files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if s, ok := funcSymbol(decl); ok {
+					add(s)
+				}
+			case *ast.GenDecl:
+				// the type of a const spec without one and without values
+				// is that of the spec before, as in iota groups
+				var carried ast.Expr
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.IsExported() {
+							for _, s := range typeSymbols(spec) {
+								add(s)
+							}
+						}
+					case *ast.ValueSpec:
+						kind := "var"
+						if decl.Tok == token.CONST {
+							kind = "const"
+						}
+						if spec.Type != nil || len(spec.Values) > 0 {
+							carried = spec.Type
+						}
+						for _, n := range spec.Names {
+							if n.IsExported() {
+								typ := valueType(p, n, carried)
+								if typ != "" {
+									typ = " " + typ
+								}
+								add(symbol{kind: kind, name: n.Name, text: kind + " " + n.Name + typ, sig: typ})
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return syms, nil
+}
+
+func funcSymbol(decl *ast.FuncDecl) (symbol, bool) {
+	if !decl.Name.IsExported() {
+		return symbol{}, false
+	}
+	d := *decl
+	d.Doc, d.Body = nil, nil
+	s := symbol{kind: "func", name: decl.Name.Name, text: printNode(&d)}
+
+	var rename map[string]string
+	var recv string
+	if decl.Recv != nil && len(decl.Recv.List) == 1 {
+		typ := decl.Recv.List[0].Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			recv = "*"
+			typ = star.X
+		}
+		var params []ast.Expr
+		switch t := typ.(type) {
+		case *ast.IndexExpr:
+			typ, params = t.X, []ast.Expr{t.Index}
+		case *ast.IndexListExpr:
+			typ, params = t.X, t.Indices
+		}
+		base, ok := typ.(*ast.Ident)
+		if !ok || !base.IsExported() {
+			return symbol{}, false
+		}
+		s.kind = "method"
+		s.name = base.Name + "." + decl.Name.Name
+		s.parent = "type " + base.Name
+		recv = "(" + recv + base.Name + ") "
+		rename = map[string]string{}
+		for i, p := range params {
+			if id, ok := p.(*ast.Ident); ok {
+				rename[id.Name] = fmt.Sprintf("$%d", i)
+			}
+		}
+	}
+
+	s.tparams, rename = typeParams(decl.Type.TypeParams, rename)
+	s.sig = recv + normalize(&ast.FuncType{Params: decl.Type.Params, Results: decl.Type.Results}, rename)
+	return s, true
+}
+
+func typeSymbols(spec *ast.TypeSpec) []symbol {
+	name := spec.Name.Name
+	sp := *spec
+	sp.Doc, sp.Comment = nil, nil
+	switch spec.Type.(type) {
+	case *ast.StructType:
+		// members are symbols of their own
+		sp.Type = ast.NewIdent("struct{...}")
+	case *ast.InterfaceType:
+		sp.Type = ast.NewIdent("interface{...}")
+	}
+	t := symbol{kind: "type", name: name, text: "type " + printNode(&sp)}
+	tparams, rename := typeParams(spec.TypeParams, nil)
+	t.tparams = tparams
+	syms := []symbol{t}
+	parent := t.key()
+
+	switch typ := spec.Type.(type) {
+	case *ast.StructType:
+		if spec.Assign.IsValid() {
+			break
+		}
+		syms[0].sig = "struct"
+		for _, f := range typ.Fields.List {
+			names := f.Names
+			if len(names) == 0 {
+				// an embedded field is named after its type
+				names = []*ast.Ident{ast.NewIdent(embeddedName(f.Type))}
+			}
+			for _, n := range names {
+				if n.IsExported() {
+					syms = append(syms, symbol{
+						kind:   "field",
+						name:   name + "." + n.Name,
+						text:   n.Name + " " + printNode(f.Type),
+						sig:    normalize(f.Type, rename),
+						parent: parent,
+					})
+				}
+			}
+		}
+		return syms
+	case *ast.InterfaceType:
+		if spec.Assign.IsValid() {
+			break
+		}
+		syms[0].sig = "interface"
+		var sealed bool
+		var elems, elemsText []string
+		for _, f := range typ.Methods.List {
+			if len(f.Names) == 0 {
+				elems = append(elems, normalize(f.Type, rename))
+				elemsText = append(elemsText, printNode(f.Type))
+				continue
+			}
+			if !f.Names[0].IsExported() {
+				sealed = true
+			}
+		}
+		for _, f := range typ.Methods.List {
+			if len(f.Names) == 0 || !f.Names[0].IsExported() {
+				continue
+			}
+			m := f.Names[0].Name
+			syms = append(syms, symbol{
+				kind:   "interface method",
+				name:   name + "." + m,
+				text:   m + strings.TrimPrefix(printNode(f.Type), "func"),
+				sig:    normalize(f.Type, rename),
+				sealed: sealed,
+				parent: parent,
+			})
+		}
+		if len(elems) > 0 {
+			// embedded interfaces and type terms, in order of declaration
+			syms = append(syms, symbol{
+				kind:   "type set",
+				name:   name,
+				text:   strings.Join(elemsText, "; "),
+				sig:    strings.Join(elems, "; "),
+				parent: parent,
+			})
+		}
+		return syms
+	}
+
+	if spec.Assign.IsValid() {
+		syms[0].sig = "= " + normalize(spec.Type, rename)
+	} else {
+		syms[0].sig = normalize(spec.Type, rename)
+	}
+	return syms
+}
+
+// typeParams returns the normalized type parameter list and adds the names of
+// the parameters to rename, numbered after those already in it.
+func typeParams(list *ast.FieldList, rename map[string]string) (string, map[string]string) {
+	if list == nil || len(list.List) == 0 {
+		return "", rename
+	}
+	if rename == nil {
+		rename = map[string]string{}
+	}
+	for _, f := range list.List {
+		for _, n := range f.Names {
+			rename[n.Name] = fmt.Sprintf("$%d", len(rename))
+		}
+	}
+	var parts []string
+	for _, f := range list.List {
+		c := normalize(f.Type, rename)
+		for range f.Names {
+			parts = append(parts, c)
+		}
+	}
+	return "[" + strings.Join(parts, ", ") + "]", rename
+}
+
+// unnamed returns list with one unnamed field per parameter.
+func unnamed(list *ast.FieldList) *ast.FieldList {
+	if list == nil {
+		return nil
+	}
+	out := &ast.FieldList{}
+	for _, f := range list.List {
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			out.List = append(out.List, &ast.Field{Type: f.Type})
+		}
+	}
+	return out
+}
+
+func embeddedName(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// normalize prints node on one line, without parameter names and with the
+// identifiers in rename replaced.
+func normalize(node ast.Node, rename map[string]string) string {
+	node = clone(node)
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncType:
+			// parameter names are not part of the API
+			n.Params, n.Results = unnamed(n.Params), unnamed(n.Results)
+		case *ast.SelectorExpr:
+			// only the package part can be a type parameter
+			ast.Inspect(n.X, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					if r, ok := rename[id.Name]; ok {
+						id.Name = r
+					}
+				}
+				return true
+			})
+			return false
+		case *ast.Ident:
+			if r, ok := rename[n.Name]; ok {
+				n.Name = r
+			}
+		}
+		return true
+	})
+	return printNode(node)
+}
+
+// printNode prints node on one line, without comments.
+func printNode(node ast.Node) string {
+	node = clone(node)
+	var b bytes.Buffer
+	printer.Fprint(&b, token.NewFileSet(), node)
+	return b.String()
+}
+
+// clone returns a deep copy of node with every position cleared, so that it
+// prints the same wherever it was declared.
+func clone(node ast.Node) ast.Node {
+	return cloneValue(reflect.ValueOf(node)).Interface().(ast.Node)
+}
+
+var (
+	posType     = reflect.TypeOf(token.NoPos)
+	objectType  = reflect.TypeOf((*ast.Object)(nil))
+	commentType = reflect.TypeOf((*ast.CommentGroup)(nil))
+)
+
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || v.Type() == objectType || v.Type() == commentType {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(cloneValue(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(cloneValue(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Type != posType {
+				c.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+		return c
+	}
+	return v
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checker type-checks the packages of a module from source, so that the API
+// records the types of vars and consts declared without one. Other packages
+// come from the export data of the toolchain; where that fails, types that
+// depend on them are invalid and the declared types are used instead.
+type checker struct {
+	root, modPath string
+	fset          *token.FileSet
+	std           types.Importer
+	pkgs          map[string]*checkedPackage
+}
+
+// checkedPackage is a parsed and type-checked package. pkg is nil while it
+// is being checked, and files is nil if the directory has no importable
+// package.
+type checkedPackage struct {
+	files []*ast.File
+	info  *types.Info
+	pkg   *types.Package
+}
+
+func newChecker(root, modPath string) *checker {
+	return &checker{
+		root:    root,
+		modPath: modPath,
+		fset:    token.NewFileSet(),
+		std:     importer.Default(),
+		pkgs:    map[string]*checkedPackage{},
+	}
+}
+
+// load parses and type-checks the package with the given import path, which
+// must be in the module. Type errors are ignored.
+func (c *checker) load(path string) (*checkedPackage, error) {
+	if p, ok := c.pkgs[path]; ok {
+		return p, nil
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(path, c.modPath), "/")
+	files, err := parseDir(c.fset, filepath.Join(c.root, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, err
+	}
+	p := &checkedPackage{files: files}
+	c.pkgs[path] = p
+	if files == nil {
+		return p, nil
+	}
+	p.info = &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	conf := types.Config{Importer: c, Error: func(error) {}, FakeImportC: true}
+	pkg, _ := conf.Check(path, c.fset, files, p.info)
+	p.pkg = pkg
+	return p, nil
+}
+
+func (c *checker) Import(path string) (*types.Package, error) {
+	if path != c.modPath && !strings.HasPrefix(path, c.modPath+"/") {
+		return c.std.Import(path)
+	}
+	p, err := c.load(path)
+	if err != nil {
+		return nil, err
+	}
+	if p.pkg == nil {
+		return nil, errors.New("import cycle or no package at " + path)
+	}
+	return p.pkg, nil
+}
+
+// parseDir returns the files of the importable package in dir, sorted by
+// name, or nil if there is none. Tests and files excluded by build
+// constraints are skipped.
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		if strings.HasSuffix(fi.Name(), "_test.go") {
+			return false
+		}
+		ok, err := build.Default.MatchFile(dir, fi.Name())
+		return err == nil && ok
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	var pkg *ast.Package
+	for name, p := range pkgs {
+		if name != "main" {
+			pkg = p
+		}
+	}
+	if pkg == nil {
+		return nil, nil
+	}
+	var names []string
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	files := make([]*ast.File, len(names))
+	for i, name := range names {
+		files[i] = pkg.Files[name]
+	}
+	return files, nil
+}
+
+// valueType returns the type of the var or const declared by name, as
+// inferred by the type checker, or declared, the type written in its spec
+// or carried over from an earlier spec of a const group.
+func valueType(p *checkedPackage, name *ast.Ident, declared ast.Expr) string {
+	if obj := p.info.Defs[name]; obj != nil && p.pkg != nil {
+		typ := types.TypeString(obj.Type(), func(other *types.Package) string {
+			if other == p.pkg {
+				return ""
+			}
+			return other.Name()
+		})
+		if !strings.Contains(typ, "invalid type") {
+			return typ
+		}
+	}
+	if declared == nil {
+		return ""
+	}
+	return printNode(declared)
+}
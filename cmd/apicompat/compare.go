@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// change is a difference between two APIs.
+type change struct {
+	pkg      string
+	breaking bool
+	what     string // e.g. "removed func F"
+	old, new string // declarations, if relevant
+}
+
+// compare returns the changes from old to new, sorted by package and symbol.
+func compare(old, new api) []change {
+	var changes []change
+	for pkg, oldSyms := range old {
+		newSyms, ok := new[pkg]
+		if !ok {
+			changes = append(changes, change{pkg: pkg, breaking: true, what: "removed package"})
+			continue
+		}
+		for key, o := range oldSyms {
+			n, ok := newSyms[key]
+			if !ok {
+				if _, ok := newSyms[o.parent]; o.parent != "" && !ok {
+					// covered by the removal of the type
+					continue
+				}
+				changes = append(changes, change{pkg: pkg, breaking: true, what: "removed " + key, old: o.text})
+				continue
+			}
+			if o.tparams != n.tparams {
+				changes = append(changes, change{pkg: pkg, breaking: true, what: "changed type parameters of " + key, old: o.text, new: n.text})
+			} else if o.sig != n.sig {
+				changes = append(changes, change{pkg: pkg, breaking: true, what: "changed " + key, old: o.text, new: n.text})
+			}
+		}
+		for key, n := range newSyms {
+			if _, ok := oldSyms[key]; ok {
+				continue
+			}
+			_, parentExisted := oldSyms[n.parent]
+			switch {
+			case n.parent != "" && !parentExisted:
+				// covered by the addition of the type
+			case n.kind == "interface method" && !n.sealed:
+				changes = append(changes, change{pkg: pkg, breaking: true, what: "added method to interface: " + key, new: n.text})
+			case n.kind == "type set":
+				changes = append(changes, change{pkg: pkg, breaking: true, what: "added embedded elements to " + key, new: n.text})
+			default:
+				changes = append(changes, change{pkg: pkg, what: "added " + key, new: n.text})
+			}
+		}
+	}
+	for pkg := range new {
+		if _, ok := old[pkg]; !ok {
+			changes = append(changes, change{pkg: pkg, what: "added package"})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].pkg != changes[j].pkg {
+			return changes[i].pkg < changes[j].pkg
+		}
+		return changes[i].what < changes[j].what
+	})
+	return changes
+}
+
+// report writes changes as Markdown, breaking changes first.
+func report(w io.Writer, oldRef, newRef string, changes []change) {
+	fmt.Fprintf(w, "# API changes from %s to %s\n", oldRef, newRef)
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "\nNo API changes.")
+		return
+	}
+	for _, breaking := range []bool{true, false} {
+		title := "Compatible changes"
+		if breaking {
+			title = "Breaking changes"
+		}
+		var pkg string
+		for _, c := range changes {
+			if c.breaking != breaking {
+				continue
+			}
+			if title != "" {
+				fmt.Fprintf(w, "\n## %s\n", title)
+				title = ""
+			}
+			if c.pkg != pkg {
+				pkg = c.pkg
+				fmt.Fprintf(w, "\n### %s\n\n", pkg)
+			}
+			fmt.Fprintf(w, "- %s\n", c.what)
+			if c.old != "" && c.new != "" {
+				fmt.Fprintf(w, "  - old: `%s`\n  - new: `%s`\n", oneLine(c.old), oneLine(c.new))
+			} else if c.new != "" {
+				fmt.Fprintf(w, "  - `%s`\n", oneLine(c.new))
+			} else if c.old != "" {
+				fmt.Fprintf(w, "  - `%s`\n", oneLine(c.old))
+			}
+		}
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
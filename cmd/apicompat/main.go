@@ -0,0 +1,157 @@
+// Command apicompat reports the changes to the exported API of a module
+// between two git revisions, split into breaking and compatible changes, as
+// Markdown suitable for release notes:
+//
+//	apicompat [-C dir] [-check] old [new]
+//
+// Without new, the working tree is compared with old. Breaking changes are
+// removed packages and symbols, changed signatures, types and type parameter
+// constraints, and methods or embedded elements added to interfaces that can
+// be implemented outside their package. With -check, apicompat exits with
+// status 1 if there are breaking changes.
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("apicompat", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("C", ".", "root of the module, in a git repository")
+	check := fs.Bool("check", false, "exit with status 1 if there are breaking changes")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fmt.Fprintln(stderr, "usage: apicompat [-C dir] [-check] old [new]")
+		return 2
+	}
+
+	oldRef, newRef := fs.Arg(0), fs.Arg(1)
+	old, err := loadRef(*dir, oldRef)
+	if err != nil {
+		fmt.Fprintln(stderr, "apicompat:", err)
+		return 1
+	}
+	var new api
+	if newRef == "" {
+		newRef = "the working tree"
+		new, err = loadAPI(*dir)
+	} else {
+		new, err = loadRef(*dir, newRef)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "apicompat:", err)
+		return 1
+	}
+
+	changes := compare(old, new)
+	report(stdout, oldRef, newRef, changes)
+	if *check {
+		for _, c := range changes {
+			if c.breaking {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// loadRef returns the API of the module in dir at a git revision. The Go
+// files of the revision are extracted to a temporary directory, so the
+// working tree is left alone.
+func loadRef(dir, ref string) (api, error) {
+	prefix, err := git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "apicompat-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	cmd := exec.CommandContext(context.Background(), "git", "archive", "--format=tar", ref, ".")
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	extractErr := extract(out, tmp, strings.TrimSpace(prefix))
+	io.Copy(io.Discard, out)
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("git archive %s: %v: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+	if extractErr != nil {
+		return nil, extractErr
+	}
+	return loadAPI(tmp)
+}
+
+// extract writes the go.mod and Go files of a tar stream to dir, with prefix
+// removed from their names.
+func extract(r io.Reader, dir, prefix string) error {
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(h.Name, prefix)
+		if h.Typeflag != tar.TypeReg || !(strings.HasSuffix(name, ".go") || filepath.Base(name) == "go.mod") {
+			continue
+		}
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if !strings.HasPrefix(p, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("bad file name in archive: %s", h.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		f, err := os.Create(p)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const oldSrc = `package p
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+type Pool[T any] struct {
+	Size int
+	Name string
+	hidden int
+}
+
+func NewPool[T any](size int, handler func(T)) *Pool[T] { return nil }
+
+func (p *Pool[T]) Run(v T) bool { return false }
+
+func Max[T constraints.Ordered](a, b T) T { return a }
+
+func Renamed[K comparable](key K) {}
+
+type Queue[T any] interface {
+	Push(T)
+}
+
+type Sealed interface {
+	Get() int
+	sealed()
+}
+
+type Number interface {
+	~int | ~int64
+}
+
+type Gone struct{ A int }
+
+func Removed() {}
+
+const Limit int = 3
+
+var ErrX error
+
+const Size = 32
+
+const Version = 1
+
+type Kind int
+
+type Level uint8
+
+const (
+	KindA Kind = iota
+	KindB
+	KindC
+)
+
+var ErrY = errors.New("y")
+
+var Timeout = time.Second
+`
+
+const newSrc = `package p
+
+import (
+	"errors"
+
+	"golang.org/x/exp/constraints"
+)
+
+type Pool[T any] struct {
+	Size int64
+	Extra bool
+	hidden int
+}
+
+func NewPool[T any](size int, handler func(T), timeout int) *Pool[T] { return nil }
+
+func (p Pool[T]) Run(v T) bool { return false }
+
+func (p *Pool[T]) Stop() {}
+
+func Max[T constraints.Integer](a, b T) T { return a }
+
+func Renamed[T comparable](k T) {}
+
+type Queue[T any] interface {
+	Push(T)
+	Len() int
+}
+
+type Sealed interface {
+	Get() int
+	Set(int)
+	sealed()
+}
+
+type Number interface {
+	~int | ~int64 | ~int32
+}
+
+func Added() {}
+
+const Limit int = 4
+
+var ErrX error
+
+const Size = 32.0
+
+const Version = 2
+
+type Kind int
+
+type Level uint8
+
+const (
+	KindA Level = iota
+	KindB
+	KindC
+)
+
+var ErrY = errors.New("why")
+
+var Timeout = 1000
+`
+
+func writeModule(t *testing.T, src string, extra map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/m\n",
+		"p/p.go":          src,
+		"p/p_test.go":     "package p\n\nfunc TestOnly() {}\n",
+		"cmd/c/c.go":      "package main\n\nfunc Exported() {}\n",
+		"internal/i/i.go": "package i\n\nfunc Exported() {}\n",
+	}
+	for name, content := range extra {
+		files[name] = content
+	}
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestCompare(t *testing.T) {
+	old, err := loadAPI(writeModule(t, oldSrc, map[string]string{"gone/g.go": "package gone\n"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := loadAPI(writeModule(t, newSrc, map[string]string{"fresh/f.go": "package fresh\n"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(old) != 2 {
+		t.Fatalf("loaded packages %v, want p and gone only", old)
+	}
+
+	got := map[string]bool{}
+	for _, c := range compare(old, new) {
+		got[strings.TrimPrefix(c.pkg, "example.com/m/")+": "+c.what] = c.breaking
+	}
+	want := map[string]bool{
+		"fresh: added package":                                     false,
+		"gone: removed package":                                    true,
+		"p: added func Added":                                      false,
+		"p: added field Pool.Extra":                                false,
+		"p: added method Pool.Stop":                                false,
+		"p: added method to interface: interface method Queue.Len": true,
+		"p: added interface method Sealed.Set":                     false,
+		"p: changed field Pool.Size":                               true,
+		"p: changed func NewPool":                                  true,
+		"p: changed method Pool.Run":                               true,
+		"p: changed type parameters of func Max":                   true,
+		"p: changed type set Number":                               true,
+		"p: removed field Pool.Name":                               true,
+		"p: removed func Removed":                                  true,
+		"p: removed type Gone":                                     true,
+		"p: changed const Size":                                    true,
+		"p: changed const KindA":                                   true,
+		"p: changed const KindB":                                   true,
+		"p: changed const KindC":                                   true,
+		"p: changed var Timeout":                                   true,
+	}
+	for k, v := range want {
+		if b, ok := got[k]; !ok || b != v {
+			t.Errorf("%s: got breaking=%v (reported %v), want %v", k, b, ok, v)
+		}
+	}
+	for k := range got {
+		if _, ok := want[k]; !ok {
+			t.Errorf("unexpected change %s", k)
+		}
+	}
+
+	var b bytes.Buffer
+	report(&b, "v1", "v2", compare(old, new))
+	out := b.String()
+	breaking, compatible := strings.Index(out, "## Breaking changes"), strings.Index(out, "## Compatible changes")
+	if breaking < 0 || compatible < breaking {
+		t.Errorf("report sections missing or out of order:\n%s", out)
+	}
+	if !strings.Contains(out, "  - old: `func NewPool[T any](size int, handler func(T)) *Pool[T]`\n") {
+		t.Errorf("report misses the old signature:\n%s", out)
+	}
+}
+
+func TestRunGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	root := writeModule(t, oldSrc, nil)
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	gitRun("init", "-q")
+	gitRun("add", "-A")
+	gitRun("commit", "-q", "-m", "v1")
+	gitRun("tag", "v1")
+	if err := os.WriteFile(filepath.Join(root, "p", "p.go"), []byte(newSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-C", root, "v1", "v1"}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "No API changes.") {
+		t.Fatalf("same ref: exit code %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	stdout.Reset()
+	if code := run([]string{"-C", root, "-check", "v1"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("working tree: exit code %d, want 1: %s%s", code, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "removed func Removed") {
+		t.Errorf("report misses a removal:\n%s", stdout.String())
+	}
+	if code := run([]string{"-C", root, "nope"}, &stdout, &stderr); code != 1 {
+		t.Errorf("bad ref: exit code %d, want 1", code)
+	}
+}
+
+func TestUnresolvedTypes(t *testing.T) {
+	root := writeModule(t, `package p
+
+import "example.com/missing"
+
+const (
+	A missing.Kind = iota
+	B
+)
+
+var V = missing.New()
+`, nil)
+	a, err := loadAPI(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syms := a["example.com/m/p"]
+	for key, want := range map[string]string{
+		"const A": " missing.Kind",
+		"const B": " missing.Kind",
+		"var V":   "",
+	} {
+		if got := syms[key].sig; got != want {
+			t.Errorf("%s: sig %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindModules(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore":                 "/build/\n*.tmp\n!keep.tmp\n",
+		"go.mod":                     "module example.com/root\n\ngo 1.19\n",
+		"a/go.mod":                   "// comment\nmodule \"example.com/a\" // trailing\n",
+		"a/vendor/modules.txt":       "",
+		"a/vendor/x/go.mod":          "module example.com/x\n",
+		"b/empty/go.mod":             "go 1.19\n",
+		"b/c/go.mod":                 "module example.com/c\n",
+		"b/.gitignore":               "deep/**/gen\n",
+		"b/deep/x/gen/go.mod":        "module example.com/gen\n",
+		"b/deep/x/kept/go.mod":       "module example.com/kept\n",
+		"build/go.mod":               "module example.com/build\n",
+		"d/build/go.mod":             "module example.com/d/build\n",
+		"e.tmp/go.mod":               "module example.com/tmp\n",
+		"keep.tmp/go.mod":            "module example.com/keep\n",
+		"testdata/go.mod":            "module example.com/testdata\n",
+		".hidden/go.mod":             "module example.com/hidden\n",
+		"_skip/go.mod":               "module example.com/skip\n",
+		"modulex/go.mod":             "modulex example.com/modulex\n",
+		"notmod/go.mod.bak":          "module example.com/bak\n",
+		"notmod/sub/go.mod/file.txt": "",
+	})
+
+	mods, err := findModules(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, m := range mods {
+		rel, _ := filepath.Rel(root, m.dir)
+		got[filepath.ToSlash(rel)] = m.path
+		if m.vendored != (rel == "a") {
+			t.Errorf("%s: vendored = %v", rel, m.vendored)
+		}
+	}
+	want := map[string]string{
+		".":             "example.com/root",
+		"a":             "example.com/a",
+		"b/c":           "example.com/c",
+		"b/deep/x/kept": "example.com/kept",
+		"d/build":       "example.com/d/build",
+		"keep.tmp":      "example.com/keep",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	var ig ignorer
+	for _, line := range []string{"# comment", "", "*.log", "/only-root", "docs/*.md", "out/", "!important.log", `\!bang`} {
+		if r, ok := parseIgnoreRule("", line); ok {
+			ig.rules = append(ig.rules, r)
+		}
+	}
+	for _, c := range []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"x.log", false, true},
+		{"a/b/x.log", false, true},
+		{"important.log", false, false},
+		{"only-root", false, true},
+		{"a/only-root", false, false},
+		{"docs/a.md", false, true},
+		{"a/docs/a.md", false, false},
+		{"out", true, true},
+		{"out", false, false},
+		{"a/out", true, true},
+		{"!bang", false, true},
+		{"# comment", false, false},
+	} {
+		if got := ig.ignored(c.path, c.isDir); got != c.want {
+			t.Errorf("ignored(%q, %v) = %v, want %v", c.path, c.isDir, got, c.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"good/go.mod": "module example.com/good\n\ngo 1.19\n",
+		"bad/go.mod":  "module example.com/bad\n\nnot a directive\n",
+	})
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"tidy", "-dry-run", root}, &stdout, &stderr); code != 0 {
+		t.Fatalf("dry run exited with %d: %s", code, stderr.String())
+	}
+	if n := strings.Count(stdout.String(), "dry-run"); n != 2 {
+		t.Errorf("dry run printed %d commands:\n%s", n, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run(context.Background(), []string{"verify", "-j", "1", root}, &stdout, &stderr); code != 1 {
+		t.Fatalf("verify exited with %d, want 1:\n%s", code, stdout.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "FAIL  "+filepath.Join(root, "bad")) || !strings.Contains(out, "ok    "+filepath.Join(root, "good")) ||
+		!strings.Contains(out, "1 of 2 modules failed") {
+		t.Errorf("unexpected summary:\n%s", out)
+	}
+
+	for _, args := range [][]string{nil, {"nope"}, {"tidy", "-j", "0"}, {"tidy", "a", "b"}} {
+		if code := run(context.Background(), args, &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) exited with %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Command automod runs maintenance tasks on every Go module under a
+// directory tree.
+//
+// Usage:
+//
+//	automod <command> [flags] [dir]
+//
+// The commands are:
+//
+//	tidy    run go mod tidy in each module
+//	update  run go get -u ./... and go mod tidy in each module
+//	verify  run go mod verify in each module
+//	list    print each module and its path
+//
+// Modules that vendor their dependencies are re-vendored after tidy and
+// update. dir defaults to the current directory. automod exits with status 1
+// if any module failed and 2 on bad usage.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+var commands = map[string][][]string{
+	"tidy":   {{"go", "mod", "tidy"}},
+	"update": {{"go", "get", "-u", "./..."}, {"go", "mod", "tidy"}},
+	"verify": {{"go", "mod", "verify"}},
+	"list":   nil,
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: automod <tidy|update|verify|list> [flags] [dir]")
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	name := args[0]
+	steps, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "automod: unknown command %q\n", name)
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("automod "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dryRun := fs.Bool("dry-run", false, "print the commands instead of running them")
+	jobs := fs.Int("j", runtime.GOMAXPROCS(0), "maximum number of modules processed at once")
+	verbose := fs.Bool("v", false, "print the output of successful commands too")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 || *jobs < 1 {
+		usage(stderr)
+		return 2
+	}
+	root := "."
+	if fs.NArg() == 1 {
+		root = fs.Arg(0)
+	}
+
+	mods, err := findModules(root)
+	if err != nil {
+		fmt.Fprintln(stderr, "automod:", err)
+		return 1
+	}
+	if len(mods) == 0 {
+		fmt.Fprintf(stderr, "automod: no modules under %s\n", root)
+		return 1
+	}
+
+	if name == "list" {
+		w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		for _, m := range mods {
+			fmt.Fprintf(w, "%s\t%s\n", m.dir, m.path)
+		}
+		w.Flush()
+		return 0
+	}
+
+	results := make([]result, len(mods))
+	sem := make(chan struct{}, *jobs)
+	var wg sync.WaitGroup
+	for i, m := range mods {
+		cmds := steps
+		if m.vendored {
+			cmds = append(cmds[:len(cmds):len(cmds)], []string{"go", "mod", "vendor"})
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, m module, cmds [][]string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = process(ctx, m, cmds, *dryRun)
+		}(i, m, cmds)
+	}
+	wg.Wait()
+
+	return report(stdout, results, *verbose)
+}
+
+type result struct {
+	mod     module
+	cmds    [][]string
+	dryRun  bool
+	output  []byte
+	err     error
+	elapsed time.Duration
+}
+
+// process runs cmds in the directory of m, stopping at the first failure.
+func process(ctx context.Context, m module, cmds [][]string, dryRun bool) result {
+	r := result{mod: m, cmds: cmds, dryRun: dryRun}
+	if dryRun {
+		return r
+	}
+	start := time.Now()
+	var out bytes.Buffer
+	for _, args := range cmds {
+		fmt.Fprintf(&out, "$ %s\n", strings.Join(args, " "))
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = m.dir
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			r.err = fmt.Errorf("%s: %w", strings.Join(args, " "), err)
+			break
+		}
+	}
+	r.output = out.Bytes()
+	r.elapsed = time.Since(start)
+	return r
+}
+
+// report prints the output of failed modules, then one summary line per
+// module, and returns the exit code.
+func report(w io.Writer, results []result, verbose bool) int {
+	var failed int
+	for _, r := range results {
+		if r.err != nil || (verbose && len(r.output) > 0) {
+			fmt.Fprintf(w, "== %s\n%s\n", r.mod.dir, r.output)
+		}
+		if r.err != nil {
+			failed++
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, r := range results {
+		switch {
+		case r.dryRun:
+			for _, args := range r.cmds {
+				fmt.Fprintf(tw, "dry-run\t%s\t%s\n", r.mod.dir, strings.Join(args, " "))
+			}
+		case r.err != nil:
+			fmt.Fprintf(tw, "FAIL\t%s\t%s\t%v\n", r.mod.dir, r.elapsed.Round(time.Millisecond), r.err)
+		default:
+			fmt.Fprintf(tw, "ok\t%s\t%s\n", r.mod.dir, r.elapsed.Round(time.Millisecond))
+		}
+	}
+	tw.Flush()
+
+	if failed > 0 {
+		fmt.Fprintf(w, "%d of %d modules failed\n", failed, len(results))
+		return 1
+	}
+	return 0
+}
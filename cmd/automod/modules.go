@@ -0,0 +1,85 @@
+package main
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// module is a directory holding a go.mod file with a module directive.
+type module struct {
+	dir  string
+	path string
+	// vendored is set if the module vendors its dependencies.
+	vendored bool
+}
+
+// findModules returns the modules under root, in walk order. Like the go
+// command, it skips vendor and testdata directories and directories starting
+// with "." or "_", and it also skips everything ignored by .gitignore files.
+func findModules(root string) ([]module, error) {
+	var mods []module
+	var ig ignorer
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if rel != "." {
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if ig.ignored(filepath.ToSlash(rel), true) {
+				return filepath.SkipDir
+			}
+		}
+		if err := ig.load(p, rel); err != nil {
+			return err
+		}
+
+		modPath, ok, err := readModulePath(filepath.Join(p, "go.mod"))
+		if err != nil {
+			return err
+		}
+		if ok {
+			_, err := os.Stat(filepath.Join(p, "vendor", "modules.txt"))
+			mods = append(mods, module{dir: p, path: modPath, vendored: err == nil})
+		}
+		return nil
+	})
+	return mods, err
+}
+
+// readModulePath returns the path of the module directive of a go.mod file.
+// It reports false if the file does not exist or has no module directive.
+func readModulePath(name string) (string, bool, error) {
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err != nil || !fi.Mode().IsRegular() {
+		return "", false, err
+	}
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return "", false, err
+	}
+	p := modfile.ModulePath(b)
+	return p, p != "", nil
+}
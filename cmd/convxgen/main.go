@@ -0,0 +1,242 @@
+// Command convxgen generates the converter registrations of typex/convx.
+//
+// Without a spec it writes the registrations of every built-in conversion,
+// which is how typex/convx/init.go is made:
+//
+//	//go:generate go run ../../cmd/convxgen -o init.go
+//
+// With a spec it writes the registrations between the types declared in the
+// spec and every other type known to convx, for the package the spec belongs
+// to. A spec declares one type per line as its name and its underlying type,
+// which must be a built-in numeric type, string or bool:
+//
+//	# comments start with a hash
+//	HexString string
+//	Celsius   float64
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+type kind int
+
+const (
+	signed kind = iota
+	unsigned
+	float
+	str
+	boolean
+	bytesKind
+	runesKind
+)
+
+type typ struct {
+	name string
+	kind kind
+	user bool
+}
+
+var builtins = []typ{
+	{name: "int8", kind: signed},
+	{name: "int16", kind: signed},
+	{name: "int32", kind: signed},
+	{name: "int64", kind: signed},
+	{name: "int", kind: signed},
+	{name: "uint8", kind: unsigned},
+	{name: "uint16", kind: unsigned},
+	{name: "uint32", kind: unsigned},
+	{name: "uint64", kind: unsigned},
+	{name: "uint", kind: unsigned},
+	{name: "float32", kind: float},
+	{name: "float64", kind: float},
+	{name: "string", kind: str},
+	{name: "bool", kind: boolean},
+	{name: "[]byte", kind: bytesKind},
+	{name: "[]rune", kind: runesKind},
+}
+
+func (k kind) number() bool {
+	return k == signed || k == unsigned || k == float
+}
+
+func (k kind) integer() bool {
+	return k == signed || k == unsigned
+}
+
+// converter returns the convx function converting from into to, with its
+// type arguments, or "" if convx has no such conversion.
+func converter(from, to typ) string {
+	f, t := from.kind, to.kind
+	var name string
+	args := []string{from.name, to.name}
+	switch {
+	case f.number() && t.number():
+		name = "NumberToNumber"
+	case f == str && t == signed:
+		name = "StringToInt"
+	case f == str && t == unsigned:
+		name = "StringToUint"
+	case f == str && t == float:
+		name = "StringToFloat"
+	case f == signed && t == str:
+		name = "IntToString"
+	case f == unsigned && t == str:
+		name = "UintToString"
+	case f == float && t == str:
+		name = "FloatToString"
+	case f == boolean && t.integer():
+		name = "BoolToInteger"
+	case f == boolean && t == float:
+		name = "BoolToFloat"
+	case f.integer() && t == boolean:
+		name = "IntegerToBool"
+	case f == float && t == boolean:
+		name = "FloatToBool"
+	case f == boolean && t == str:
+		name = "BoolToString"
+	case f == str && t == boolean:
+		name = "StringToBool"
+	case f == str && t == str:
+		name = "StringToString"
+	case f == boolean && t == boolean:
+		name = "BoolToBool"
+	case f == str && t == bytesKind:
+		name, args = "StringToBytes", args[:1]
+	case f == bytesKind && t == str:
+		name, args = "BytesToString", args[1:]
+	case f == str && t == runesKind:
+		name, args = "StringToRunes", args[:1]
+	case f == runesKind && t == str:
+		name, args = "RunesToString", args[1:]
+	case f == bytesKind && t == bytesKind:
+		return "BytesToBytes"
+	case f == runesKind && t == runesKind:
+		return "RunesToRunes"
+	default:
+		return ""
+	}
+	return name + "[" + strings.Join(args, ", ") + "]"
+}
+
+// generate returns the source of an init function registering conversions.
+// Without user types it registers every conversion between built-in types,
+// and must then be in package convx itself; otherwise it registers every
+// conversion involving a user type.
+func generate(pkg string, user []typ) ([]byte, error) {
+	var qual string
+	if pkg != "convx" {
+		qual = "convx."
+	}
+	types := append(append([]typ(nil), builtins...), user...)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by convxgen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	if qual != "" {
+		fmt.Fprintf(&b, "import %q\n\n", "github.com/unsafe-risk/utilx/typex/convx")
+	}
+	b.WriteString("func init() {\n")
+	for _, from := range types {
+		for _, to := range types {
+			if len(user) > 0 && !from.user && !to.user {
+				continue
+			}
+			if c := converter(from, to); c != "" {
+				fmt.Fprintf(&b, "%sRegister(%s%s)\n", qual, qual, c)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+func parseSpec(r io.Reader) ([]typ, error) {
+	kinds := map[string]kind{}
+	for _, t := range builtins {
+		if t.kind != bytesKind && t.kind != runesKind {
+			kinds[t.name] = t.kind
+		}
+	}
+
+	var types []typ
+	seen := map[string]bool{}
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want a type name and its underlying type", line)
+		}
+		name, underlying := fields[0], fields[1]
+		k, ok := kinds[underlying]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unsupported underlying type %q", line, underlying)
+		}
+		if !token.IsIdentifier(name) || token.IsKeyword(name) {
+			return nil, fmt.Errorf("line %d: invalid type name %q", line, name)
+		}
+		if _, builtin := kinds[name]; builtin || seen[name] {
+			return nil, fmt.Errorf("line %d: duplicate type %q", line, name)
+		}
+		seen[name] = true
+		types = append(types, typ{name: name, kind: k, user: true})
+	}
+	return types, s.Err()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("convxgen: ")
+	spec := flag.String("spec", "", "file declaring user types; empty for the built-in conversions")
+	out := flag.String("o", "", "output file; empty for standard output")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package of the output file")
+	flag.Parse()
+	if *pkg == "" {
+		log.Fatal("no package, set -pkg or run from go generate")
+	}
+
+	var user []typ
+	if *spec != "" {
+		f, err := os.Open(*spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		user, err = parseSpec(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("%s: %v", *spec, err)
+		}
+		if len(user) == 0 {
+			log.Fatalf("%s: no types", *spec)
+		}
+	} else if *pkg != "convx" {
+		log.Fatal("the built-in conversions belong to package convx")
+	}
+
+	src, err := generate(*pkg, user)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func checkGenerated(t *testing.T, name, pkg string, user []typ) {
+	t.Helper()
+	want, err := generate(pkg, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s is stale, run go generate", name)
+	}
+}
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	checkGenerated(t, "../../typex/convx/init.go", "convx", nil)
+
+	f, err := os.Open("../../test/convx.spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	user, err := parseSpec(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGenerated(t, "../../test/convx_gen.go", "main", user)
+}
+
+func TestGenerateUser(t *testing.T) {
+	user, err := parseSpec(strings.NewReader("Celsius float64 # temperature\n\nFlag bool\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate("units", user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"convx.Register(convx.NumberToNumber[int8, Celsius])",
+		"convx.Register(convx.NumberToNumber[Celsius, Celsius])",
+		"convx.Register(convx.FloatToString[Celsius, string])",
+		"convx.Register(convx.FloatToBool[Celsius, Flag])",
+		"convx.Register(convx.BoolToFloat[Flag, Celsius])",
+		"convx.Register(convx.StringToBool[string, Flag])",
+	} {
+		if !bytes.Contains(src, []byte(want)) {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if bytes.Contains(src, []byte("[int8, int16]")) {
+		t.Error("built-in conversions registered again")
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	for _, spec := range []string{
+		"Foo\n",
+		"Foo string extra\n",
+		"Foo complex128\n",
+		"Foo []byte\n",
+		"func string\n",
+		"int string\n",
+		"Foo string\nFoo int\n",
+	} {
+		if _, err := parseSpec(strings.NewReader(spec)); err == nil {
+			t.Errorf("parseSpec(%q) succeeded", spec)
+		}
+	}
+}
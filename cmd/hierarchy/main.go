@@ -0,0 +1,70 @@
+// Command hierarchy writes the package tree of a module as a diagram, with
+// the synopsis of each package doc. It renders d2 by default, or Mermaid or
+// DOT, and is how hierarchical.d2 and hierarchical.mmd are made:
+//
+//	hierarchy [-format d2|mermaid|dot] [-o file] [-check] [dir]
+//
+// With -check it writes nothing and exits with status 1 if the file named by
+// -o differs from what it would write.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("hierarchy", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "d2", "output format: d2, mermaid or dot")
+	out := fs.String("o", "", "output file; empty for standard output")
+	check := fs.Bool("check", false, "fail if the output file is stale instead of writing it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	render, ok := renderers[*format]
+	if !ok || fs.NArg() > 1 || (*check && *out == "") {
+		fmt.Fprintln(stderr, "usage: hierarchy [-format d2|mermaid|dot] [-o file] [-check] [dir]")
+		return 2
+	}
+	root := "."
+	if fs.NArg() == 1 {
+		root = fs.Arg(0)
+	}
+
+	tree, err := loadTree(root)
+	if err != nil {
+		fmt.Fprintln(stderr, "hierarchy:", err)
+		return 1
+	}
+	var b bytes.Buffer
+	render(&b, tree)
+
+	switch {
+	case *check:
+		old, err := os.ReadFile(*out)
+		if err != nil {
+			fmt.Fprintln(stderr, "hierarchy:", err)
+			return 1
+		}
+		if !bytes.Equal(old, b.Bytes()) {
+			fmt.Fprintf(stderr, "hierarchy: %s is stale, run go generate\n", *out)
+			return 1
+		}
+	case *out == "":
+		stdout.Write(b.Bytes())
+	default:
+		if err := os.WriteFile(*out, b.Bytes(), 0o644); err != nil {
+			fmt.Fprintln(stderr, "hierarchy:", err)
+			return 1
+		}
+	}
+	return 0
+}
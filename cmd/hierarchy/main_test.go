@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommittedDiagramUpToDate(t *testing.T) {
+	for _, args := range [][]string{
+		{"-o", "../../hierarchical.d2"},
+		{"-format", "mermaid", "-o", "../../hierarchical.mmd"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(append(append([]string{"-check"}, args...), "../.."), &stdout, &stderr); code != 0 {
+			t.Errorf("%v: exit code %d: %s", args, code, stderr.String())
+		}
+	}
+}
+
+func TestTree(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":               "module example.com/m\n",
+		"m.go":                 "// Package m is the root. It has more text.\npackage m\n",
+		"a/a.go":               "package a\n",
+		"a/a_test.go":          "// Package a is documented in a test.\npackage a\n",
+		"a/b/b.go":             "// Package b does b.\npackage b\n",
+		"empty/c/README":       "",
+		"group/d/d.go":         "package d\n",
+		"nested/go.mod":        "module example.com/nested\n",
+		"nested/n.go":          "package n\n",
+		"testdata/t/t.go":      "package t\n",
+		"_skip/s.go":           "package s\n",
+		"z/z.go":               "/*\nPackage z is in a block\ncomment.\n*/\npackage z\n",
+		"group/d/doc_extra.go": "package d\n",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tree, err := loadTree(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	var dump func(n *node, depth int)
+	dump = func(n *node, depth int) {
+		lines = append(lines, strings.Repeat("  ", depth)+n.name+": "+n.doc)
+		for _, c := range n.children {
+			dump(c, depth+1)
+		}
+	}
+	dump(tree, 0)
+	got := strings.Join(lines, "\n")
+	want := strings.Join([]string{
+		"m: Package m is the root.",
+		"  a: ",
+		"    b: Package b does b.",
+		"  group: ",
+		"    d: ",
+		"  z: Package z is in a block comment.",
+	}, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	for format, want := range map[string][]string{
+		"d2":      {`"a/b": "b" {`, `"a" -> "a/b"`, "shape: class", `tooltip: "Package b does b."`},
+		"mermaid": {"graph TD", `n2["b<br/><small>Package b does b.</small>"]`, "n1 --> n2"},
+		"dot":     {`digraph "m" {`, `"a/b" [label="b\nPackage b does b."];`, `"a" -> "a/b";`},
+	} {
+		var b bytes.Buffer
+		renderers[format](&b, tree)
+		for _, w := range want {
+			if !strings.Contains(b.String(), w) {
+				t.Errorf("%s output misses %s:\n%s", format, w, b.String())
+			}
+		}
+	}
+}
+
+func TestCheckStale(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "go.mod"), []byte("module m\n"), 0o644)
+	os.WriteFile(filepath.Join(root, "m.go"), []byte("package m\n"), 0o644)
+	out := filepath.Join(root, "h.d2")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-o", out, root}, &stdout, &stderr); code != 0 {
+		t.Fatalf("write: exit code %d: %s", code, stderr.String())
+	}
+	if code := run([]string{"-check", "-o", out, root}, &stdout, &stderr); code != 0 {
+		t.Fatalf("fresh file: exit code %d: %s", code, stderr.String())
+	}
+	os.MkdirAll(filepath.Join(root, "p"), 0o755)
+	os.WriteFile(filepath.Join(root, "p", "p.go"), []byte("package p\n"), 0o644)
+	if code := run([]string{"-check", "-o", out, root}, &stdout, &stderr); code != 1 {
+		t.Fatalf("stale file: exit code %d, want 1", code)
+	}
+	if code := run([]string{"-check", root}, &stdout, &stderr); code != 2 {
+		t.Fatalf("check without -o: exit code %d, want 2", code)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var renderers = map[string]func(*bytes.Buffer, *node){
+	"d2":      renderD2,
+	"mermaid": renderMermaid,
+	"dot":     renderDOT,
+}
+
+const header = "Code generated by hierarchy. DO NOT EDIT."
+
+// walk calls f for every node below n, parents first, with their parent.
+func walk(n *node, f func(parent, n *node)) {
+	for _, c := range n.children {
+		f(n, c)
+		walk(c, f)
+	}
+}
+
+// id returns a unique identifier of n: its path, or the root name.
+func (n *node) id() string {
+	if n.path == "" {
+		return n.name
+	}
+	return n.path
+}
+
+func renderD2(b *bytes.Buffer, root *node) {
+	fmt.Fprintf(b, "# %s\n\n", header)
+	d2Node(b, root)
+	walk(root, func(parent, n *node) {
+		b.WriteByte('\n')
+		d2Node(b, n)
+		fmt.Fprintf(b, "%s -> %s\n", strconv.Quote(parent.id()), strconv.Quote(n.id()))
+	})
+}
+
+func d2Node(b *bytes.Buffer, n *node) {
+	// branches are drawn as boxes and leaves as plain text
+	shape := "text"
+	if len(n.children) > 0 {
+		shape = "class"
+	}
+	fmt.Fprintf(b, "%s: %s {\n  shape: %s\n", strconv.Quote(n.id()), strconv.Quote(n.name), shape)
+	if n.doc != "" {
+		fmt.Fprintf(b, "  tooltip: %s\n", strconv.Quote(n.doc))
+	}
+	b.WriteString("}\n")
+}
+
+func renderMermaid(b *bytes.Buffer, root *node) {
+	fmt.Fprintf(b, "%%%% %s\ngraph TD\n", header)
+	ids := map[*node]string{root: "n0"}
+	mermaidNode(b, "n0", root)
+	walk(root, func(parent, n *node) {
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[n] = id
+		mermaidNode(b, id, n)
+		fmt.Fprintf(b, "  %s --> %s\n", ids[parent], id)
+	})
+}
+
+func mermaidNode(b *bytes.Buffer, id string, n *node) {
+	label := n.name
+	if n.doc != "" {
+		label += "<br/><small>" + n.doc + "</small>"
+	}
+	fmt.Fprintf(b, "  %s[\"%s\"]\n", id, strings.ReplaceAll(label, `"`, "#quot;"))
+}
+
+func renderDOT(b *bytes.Buffer, root *node) {
+	fmt.Fprintf(b, "// %s\ndigraph %s {\n  node [shape=box];\n", header, strconv.Quote(root.name))
+	dotNode(b, root)
+	walk(root, func(parent, n *node) {
+		dotNode(b, n)
+		fmt.Fprintf(b, "  %s -> %s;\n", strconv.Quote(parent.id()), strconv.Quote(n.id()))
+	})
+	b.WriteString("}\n")
+}
+
+func dotNode(b *bytes.Buffer, n *node) {
+	label := n.name
+	if n.doc != "" {
+		label += "\n" + n.doc
+	}
+	fmt.Fprintf(b, "  %s [label=%s];\n", strconv.Quote(n.id()), strconv.Quote(label))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// node is a directory of the module that is a package or holds packages.
+type node struct {
+	// path is slash separated and relative to the module root, which has "".
+	path     string
+	name     string
+	doc      string // synopsis of the package doc
+	pkg      bool   // whether the directory is a package
+	children []*node
+}
+
+// loadTree returns the package tree of the module rooted at root. Like the
+// go command, it skips vendor and testdata directories, directories starting
+// with "." or "_", and nested modules.
+func loadTree(root string) (*node, error) {
+	modPath, err := modulePath(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	top := &node{name: path.Base(modPath)}
+	nodes := map[string]*node{".": top}
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		n := nodes["."]
+		if rel != "." {
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			n = &node{path: filepath.ToSlash(rel), name: name}
+			nodes[rel] = n
+			parent := nodes[filepath.Dir(rel)]
+			parent.children = append(parent.children, n)
+		}
+		n.doc, n.pkg, err = synopsis(p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	prune(top)
+	return top, nil
+}
+
+// synopsis returns the first sentence of the doc of the package in dir, and
+// whether there is a package at all.
+func synopsis(dir string) (string, bool, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return "", false, err
+	}
+	var names []string
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, f := range pkgs[name].Files {
+			if f.Doc != nil {
+				return new(doc.Package).Synopsis(f.Doc.Text()), true, nil
+			}
+		}
+	}
+	return "", len(names) > 0, nil
+}
+
+// prune removes directories without packages and sorts children by name.
+// It reports whether n is kept.
+func prune(n *node) bool {
+	kept := n.children[:0]
+	for _, c := range n.children {
+		if prune(c) {
+			kept = append(kept, c)
+		}
+	}
+	n.children = kept
+	sort.Slice(n.children, func(i, j int) bool {
+		return n.children[i].name < n.children[j].name
+	})
+	return len(n.children) > 0 || n.pkg
+}
+
+// modulePath returns the path of the module directive of a go.mod file.
+func modulePath(name string) (string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	if p := modfile.ModulePath(b); p != "" {
+		return p, nil
+	}
+	return "", fmt.Errorf("%s: no module directive", name)
+}
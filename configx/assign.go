@@ -0,0 +1,285 @@
+package configx
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unsafe-risk/utilx/typex/convx"
+)
+
+// basicTypes are the types convx converts to, by kind. Named types are
+// converted to these and then to the named type.
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Int:     reflect.TypeOf(0),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
+
+// setter assigns decoded values to fields and records where each came from.
+type setter struct {
+	origin  Origin
+	origins map[string]Origin
+	// errs collects the errors of the fields of structs, so that one bad
+	// field does not hide the others. Inner setters have neither origins nor
+	// errs and stop at the first error.
+	errs []error
+	// key decrypts encrypted secrets.
+	key []byte
+}
+
+// assign sets v from raw, a string or a value decoded from a file, and
+// records the origin of the fields it sets under path. Elements of slices and
+// maps are assigned with an inner setter that records nothing.
+func (s *setter) assign(v reflect.Value, raw any, path string) error {
+	if raw == nil {
+		return nil
+	}
+	if n, ok := raw.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			raw = i
+		} else if f, err := n.Float64(); err == nil {
+			raw = f
+		}
+	}
+
+	v = alloc(v)
+	if t, ok := raw.(time.Time); ok && v.Type() == timeType {
+		v.Set(reflect.ValueOf(t))
+		s.record(path)
+		return nil
+	}
+	if v.CanAddr() {
+		if sv, ok := v.Addr().Interface().(secretSetter); ok {
+			if err := sv.setSecret(raw, s.key); err != nil {
+				return err
+			}
+			s.record(path)
+			return nil
+		}
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			text, ok := raw.(string)
+			if !ok {
+				text = fmt.Sprint(raw)
+			}
+			if err := u.UnmarshalText([]byte(text)); err != nil {
+				return err
+			}
+			s.record(path)
+			return nil
+		}
+	}
+
+	if v.Type() == durationType {
+		if text, ok := raw.(string); ok {
+			d, err := time.ParseDuration(text)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(d))
+			s.record(path)
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		m, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("cannot set %v from %T", v.Type(), raw)
+		}
+		return s.assignStruct(v, m, path)
+
+	case reflect.Slice:
+		var items []any
+		switch raw := raw.(type) {
+		case []any:
+			items = raw
+		case string:
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				v.SetBytes([]byte(raw))
+				s.record(path)
+				return nil
+			}
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		default:
+			return fmt.Errorf("cannot set %v from %T", v.Type(), raw)
+		}
+		inner := &setter{origin: s.origin, key: s.key}
+		sv := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := inner.assign(sv.Index(i), item, ""); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		v.Set(sv)
+		s.record(path)
+		return nil
+
+	case reflect.Map:
+		m, ok := raw.(map[string]any)
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot set %v from %T", v.Type(), raw)
+		}
+		inner := &setter{origin: s.origin, key: s.key}
+		mv := reflect.MakeMapWithSize(v.Type(), len(m))
+		for k, item := range m {
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err := inner.assign(e, item, ""); err != nil {
+				return fmt.Errorf("[%q]: %w", k, err)
+			}
+			mv.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), e)
+		}
+		v.Set(mv)
+		s.record(path)
+		return nil
+
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			return fmt.Errorf("cannot set %v", v.Type())
+		}
+		v.Set(reflect.ValueOf(raw))
+		s.record(path)
+		return nil
+	}
+
+	if err := assignScalar(v, raw); err != nil {
+		return err
+	}
+	s.record(path)
+	return nil
+}
+
+func (s *setter) assignStruct(v reflect.Value, m map[string]any, path string) error {
+	keys := make(map[string]string, len(m))
+	for k := range m {
+		keys[normalize(k)] = k
+	}
+	for _, f := range structFields(v.Type()) {
+		fv := v.Field(f.index)
+		if f.inline {
+			if err := s.assignStruct(alloc(fv), m, path); err != nil {
+				return err
+			}
+			continue
+		}
+		k, ok := keys[f.key]
+		if !ok {
+			continue
+		}
+		fpath := join(path, ".", f.name)
+		if err := s.assign(fv, m[k], fpath); err != nil {
+			if s.origins == nil {
+				return fmt.Errorf("%s: %w", f.name, err)
+			}
+			s.errs = append(s.errs, &FieldError{Field: fpath, Origin: s.origin, Err: err})
+		}
+	}
+	return nil
+}
+
+func (s *setter) record(path string) {
+	if s.origins != nil {
+		s.origins[path] = s.origin
+	}
+}
+
+// assignScalar converts raw with convx. Strings are checked first, since
+// the converters of convx treat malformed numbers as zero, and so are
+// numbers that do not fit in v.
+func assignScalar(v reflect.Value, raw any) error {
+	base, ok := basicTypes[v.Kind()]
+	if !ok {
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	if text, ok := raw.(string); ok {
+		if err := checkSyntax(text, v.Kind(), base.Bits); err != nil {
+			return fmt.Errorf("invalid %v %q", v.Type(), text)
+		}
+	} else if err := checkRange(v, reflect.ValueOf(raw)); err != nil {
+		return err
+	}
+	out, ok := convx.Convert(raw, base)
+	if !ok {
+		return fmt.Errorf("cannot set %v from %T", v.Type(), raw)
+	}
+	v.Set(reflect.ValueOf(out).Convert(v.Type()))
+	return nil
+}
+
+func checkSyntax(text string, kind reflect.Kind, bits func() int) error {
+	var err error
+	switch kind {
+	case reflect.Bool:
+		_, err = strconv.ParseBool(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(text, 10, bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err = strconv.ParseUint(text, 10, bits())
+	case reflect.Float32, reflect.Float64:
+		_, err = strconv.ParseFloat(text, bits())
+	}
+	return err
+}
+
+func checkRange(v, raw reflect.Value) error {
+	var overflow bool
+	switch raw.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := raw.Int()
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			overflow = v.OverflowInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			overflow = i < 0 || v.OverflowUint(uint64(i))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := raw.Uint()
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			overflow = u > math.MaxInt64 || v.OverflowInt(int64(u))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			overflow = v.OverflowUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		f := raw.Float()
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if f != math.Trunc(f) {
+				return fmt.Errorf("%v is not an integer", f)
+			}
+			overflow = f < math.MinInt64 || f >= math.MaxInt64 || v.OverflowInt(int64(f))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if f != math.Trunc(f) {
+				return fmt.Errorf("%v is not an integer", f)
+			}
+			overflow = f < 0 || f >= math.MaxUint64 || v.OverflowUint(uint64(f))
+		case reflect.Float32:
+			overflow = v.OverflowFloat(f)
+		}
+	}
+	if overflow {
+		return fmt.Errorf("%v overflows %v", raw.Interface(), v.Type())
+	}
+	return nil
+}
@@ -1 +1,3 @@
+// Package configx loads configuration structs from defaults, files,
+// environment variables and flags.
 package configx
@@ -0,0 +1,379 @@
+package configx_test
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unsafe-risk/utilx/configx"
+)
+
+type database struct {
+	Host    string        `config:",required"`
+	Port    uint16        `default:"5432"`
+	Timeout time.Duration `default:"5s"`
+	Replica *struct {
+		Host string
+	}
+}
+
+type server struct {
+	Addr net.IP
+	Port int
+}
+
+type Common struct {
+	LogLevel string `default:"info"`
+}
+
+type config struct {
+	Common
+	Name    string `default:"app" usage:"service name"`
+	DB      database
+	Tags    []string
+	Limits  map[string]float64
+	Servers []server
+	Debug   bool
+	Token   string  `env:"SERVICE_TOKEN" flag:"-"`
+	Secret  string  `config:"-"`
+	Ratio   float32 `config:"sample_ratio" default:"0.5"`
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func env(vars map[string]string) configx.Option {
+	return configx.WithLookupEnv(func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	})
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	yml := writeFile(t, "base.yaml", `
+name: from-yaml
+log_level: debug
+db:
+  host: yaml-host
+  port: 6000
+  replica:
+    host: replica
+tags: [a, b]
+limits:
+  cpu: 1.5
+servers:
+  - addr: 10.0.0.1
+    port: 80
+  - {addr: 10.0.0.2, port: 81}
+`)
+	toml := writeFile(t, "override.toml", `
+# later files override earlier ones
+sample-ratio = 0.25
+
+[DB]
+Timeout = "1m"
+`)
+	js := writeFile(t, "last.json", `{"db": {"port": 7000}, "debug": false}`)
+
+	var c config
+	report, err := configx.Load(&c,
+		configx.WithFiles(yml, toml, js),
+		configx.WithEnv("APP_"),
+		env(map[string]string{
+			"APP_DB_PORT":   "8000",
+			"APP_TAGS":      "x, y",
+			"SERVICE_TOKEN": "t0k3n",
+			"APP_SECRET":    "ignored",
+		}),
+		configx.WithArgs([]string{"-db.port", "9000", "-debug", "-tags", "p", "-tags", "q,r", "rest"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := config{
+		Common: Common{LogLevel: "debug"},
+		Name:   "from-yaml",
+		DB: database{
+			Host:    "yaml-host",
+			Port:    9000,
+			Timeout: time.Minute,
+			Replica: &struct{ Host string }{Host: "replica"},
+		},
+		Tags:   []string{"p", "q,r"},
+		Limits: map[string]float64{"cpu": 1.5},
+		Servers: []server{
+			{Addr: net.ParseIP("10.0.0.1"), Port: 80},
+			{Addr: net.ParseIP("10.0.0.2"), Port: 81},
+		},
+		Debug: true,
+		Token: "t0k3n",
+		Ratio: 0.25,
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Fatalf("loaded\n%+v\nwant\n%+v", c, want)
+	}
+
+	origins := map[string]configx.Origin{
+		"LogLevel":        {Source: configx.SourceFile, Name: yml},
+		"Name":            {Source: configx.SourceFile, Name: yml},
+		"DB.Host":         {Source: configx.SourceFile, Name: yml},
+		"DB.Port":         {Source: configx.SourceFlag, Name: "db.port"},
+		"DB.Timeout":      {Source: configx.SourceFile, Name: toml},
+		"DB.Replica.Host": {Source: configx.SourceFile, Name: yml},
+		"Tags":            {Source: configx.SourceFlag, Name: "tags"},
+		"Limits":          {Source: configx.SourceFile, Name: yml},
+		"Servers":         {Source: configx.SourceFile, Name: yml},
+		"Debug":           {Source: configx.SourceFlag, Name: "debug"},
+		"Token":           {Source: configx.SourceEnv, Name: "SERVICE_TOKEN"},
+		"Ratio":           {Source: configx.SourceFile, Name: toml},
+	}
+	if !reflect.DeepEqual(report.Origins, origins) {
+		t.Errorf("origins\n%v\nwant\n%v", report.Origins, origins)
+	}
+	if got := report.Origin("DB.Port").String(); got != "flag -db.port" {
+		t.Errorf("origin of DB.Port = %q", got)
+	}
+	if !strings.Contains(report.String(), "Token            env SERVICE_TOKEN\n") {
+		t.Errorf("report:\n%s", report)
+	}
+	if !reflect.DeepEqual(report.Args, []string{"rest"}) {
+		t.Errorf("args = %q", report.Args)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	var c config
+	report, err := configx.Load(&c, configx.WithEnv("APP"), env(map[string]string{"APP_DB_HOST": "h"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "app" || c.LogLevel != "info" || c.DB.Port != 5432 || c.DB.Timeout != 5*time.Second || c.Ratio != 0.5 {
+		t.Errorf("defaults not applied: %+v", c)
+	}
+	if c.DB.Replica != nil {
+		t.Errorf("unset pointer allocated: %+v", c.DB.Replica)
+	}
+	if o := report.Origin("Name"); o.Source != configx.SourceDefault {
+		t.Errorf("origin of Name = %v", o)
+	}
+	if o := report.Origin("Debug"); o.Source != configx.SourceNone {
+		t.Errorf("origin of Debug = %v", o)
+	}
+}
+
+type required struct {
+	A string `config:",required"`
+	B struct {
+		C int `config:",required"`
+		D int `config:",required" default:"1"`
+	}
+	E []string `config:",required"`
+}
+
+func TestLoadErrors(t *testing.T) {
+	var r required
+	_, err := configx.Load(&r)
+	var ce *configx.Error
+	if !errors.As(err, &ce) {
+		t.Fatalf("err = %v, want *configx.Error", err)
+	}
+	if got := ce.Missing(); !reflect.DeepEqual(got, []string{"A", "B.C", "E"}) {
+		t.Errorf("missing = %q", got)
+	}
+	if !errors.Is(err, configx.ErrMissing) {
+		t.Error("errors.Is(err, ErrMissing) = false")
+	}
+	if got := err.Error(); got != "configx: missing required fields: A, B.C, E" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	path := writeFile(t, "bad.json", `{"db": {"host": "h", "port": 70000, "timeout": 1.5}, "servers": [{"port": "x"}]}`)
+	var c config
+	_, err = configx.Load(&c,
+		configx.WithFiles(path),
+		configx.WithEnv("APP"),
+		env(map[string]string{"APP_DEBUG": "maybe", "APP_DB_TIMEOUT": "soon"}),
+	)
+	msg := err.Error()
+	for _, want := range []string{
+		"DB.Port (file " + path + "): 70000 overflows uint16",
+		"DB.Timeout (file " + path + "): 1.5 is not an integer",
+		"Servers (file " + path + "): [0]: Port: invalid int \"x\"",
+		"Debug (env APP_DEBUG): invalid bool \"maybe\"",
+		"DB.Timeout (env APP_DB_TIMEOUT): time: invalid duration \"soon\"",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error misses %q:\n%s", want, msg)
+		}
+	}
+	var fe *configx.FieldError
+	if !errors.As(err, &fe) || fe.Field != "DB.Port" {
+		t.Errorf("errors.As(err, *FieldError) = %v", fe)
+	}
+
+	if _, err := configx.Load(&c, configx.WithFiles("config.ini")); !errors.Is(err, configx.ErrUnknownFormat) {
+		t.Errorf("unknown format: err = %v", err)
+	}
+	if _, err := configx.Load(&c, configx.WithFiles(filepath.Join(t.TempDir(), "none.json"))); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: err = %v", err)
+	}
+	if _, err := configx.Load(&c, configx.WithArgs([]string{"-nope"}), configx.WithFlagOutput("test", io.Discard)); err == nil || !strings.Contains(err.Error(), "-nope") {
+		t.Errorf("unknown flag: err = %v", err)
+	}
+}
+
+func TestLoadInvalidTarget(t *testing.T) {
+	var c config
+	var nilConfig *config
+	for _, dst := range []any{nil, c, nilConfig, new(int)} {
+		if _, err := configx.Load(dst); !errors.Is(err, configx.ErrInvalidTarget) {
+			t.Errorf("Load(%T): err = %v, want ErrInvalidTarget", dst, err)
+		}
+	}
+}
+
+func TestLoadSlices(t *testing.T) {
+	var c struct{ Tags []string }
+	if _, err := configx.Load(&c, configx.WithEnv("APP"), env(map[string]string{"APP_TAGS": "a, b"})); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.Tags, []string{"a", "b"}) {
+		t.Errorf("from env: %q", c.Tags)
+	}
+	if _, err := configx.Load(&c, configx.WithArgs([]string{"-tags", "a,b", "-tags", "c"})); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.Tags, []string{"a,b", "c"}) {
+		t.Errorf("from flags: %q", c.Tags)
+	}
+}
+
+func TestLoadHelp(t *testing.T) {
+	var out strings.Builder
+	var c config
+	_, err := configx.Load(&c, configx.WithArgs([]string{"-help"}), configx.WithFlagOutput("test", &out))
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("err = %v, want flag.ErrHelp", err)
+	}
+	help := out.String()
+	if strings.Contains(help, "panic") {
+		t.Fatalf("help output reports a panic:\n%s", help)
+	}
+	for _, want := range []string{"-name", "service name (default app)", "-db.port", "(default 5432)", "-sample-ratio", "(default 0.5)"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help output misses %q:\n%s", want, help)
+		}
+	}
+	if strings.Contains(help, "-token") {
+		t.Errorf("help output lists a disabled flag:\n%s", help)
+	}
+}
+
+type validated struct {
+	Min, Max int
+}
+
+func (v *validated) Validate() error {
+	if v.Min > v.Max {
+		return errors.New("min is greater than max")
+	}
+	return nil
+}
+
+func TestLoadValidate(t *testing.T) {
+	var v validated
+	_, err := configx.Load(&v, configx.WithArgs([]string{"-min", "3", "-max", "2"}))
+	if err == nil || err.Error() != "configx: min is greater than max" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestTOML(t *testing.T) {
+	type table struct {
+		S, L, ML, MLL  string
+		I, Hex         int64
+		F              float64
+		Date           time.Time
+		Day, At, Local string
+		Arr            [][]int
+		Point          struct{ X, Y int }
+		Dotted         struct{ Key struct{ Name string } }
+		Items          []struct{ Name string }
+	}
+	path := writeFile(t, "all.toml", `
+s = "tab\tquote\" \u00e9"
+l = 'C:\path'
+ml = """
+one \
+   two"""
+mll = '''
+raw \n'''
+i = -1_000
+hex = 0xff
+f = 6.5e-1
+date = 1979-05-27 07:32:00Z
+day = 1979-05-27
+at = 07:32:00.5
+local = 1979-05-27T07:32:00
+arr = [
+  [1, 2], # comment
+  [3],
+]
+point = { x = 1, y = 2 }
+dotted.key."name" = "n"
+
+[[items]]
+name = "a"
+
+[[items]]
+name = "b"
+`)
+	var tb table
+	if _, err := configx.Load(&tb, configx.WithFiles(path)); err != nil {
+		t.Fatal(err)
+	}
+	want := table{
+		S: "tab\tquote\" é", L: `C:\path`, ML: "one two", MLL: `raw \n`,
+		I: -1000, Hex: 255, F: 0.65,
+		Date:  time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+		Day:   "1979-05-27",
+		At:    "07:32:00.5",
+		Local: "1979-05-27T07:32:00",
+		Arr:   [][]int{{1, 2}, {3}},
+		Point: struct{ X, Y int }{1, 2},
+		Items: []struct{ Name string }{{"a"}, {"b"}},
+	}
+	want.Dotted.Key.Name = "n"
+	if !reflect.DeepEqual(tb, want) {
+		t.Errorf("loaded\n%+v\nwant\n%+v", tb, want)
+	}
+
+	for _, bad := range []string{
+		"a = 1\na = 2",
+		"[t]\n[t]",
+		"a = 01",
+		"a = \"open",
+		"a = 1 b = 2",
+		"a = [1, 2",
+		"a = 1\n[a]",
+	} {
+		var v struct{ A int }
+		if _, err := configx.Load(&v, configx.WithFiles(writeFile(t, "bad.toml", bad))); err == nil || !strings.Contains(err.Error(), "toml: line") {
+			t.Errorf("%q: err = %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Package featurex evaluates feature flags with percentage rollouts and
+// allow and deny lists.
+package featurex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/unsafe-risk/utilx/algox/hashx"
+	"github.com/unsafe-risk/utilx/configx"
+)
+
+// The implicit variants of flags without variants.
+const (
+	On  = "on"
+	Off = "off"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
+
+// Flag defines a feature flag. A flag is enabled for a subject, such as a
+// tenant or user ID, when the flag is enabled, the subject is not denied and
+// it is either allowed, overridden or within the rollout. Enabled subjects
+// get a variant picked by weight, or the one they are overridden to;
+// the others get the default variant.
+type Flag struct {
+	Enabled bool
+	// Rollout is the percentage of subjects the flag is enabled for, from 0
+	// to 100. Nil means every subject.
+	Rollout *float64
+	Allow   []string
+	// Deny wins over everything else.
+	Deny []string
+	// Variants make the flag a variant flag. Without variants, enabled
+	// subjects get On and the others Off.
+	Variants []Variant
+	// Default is the variant of subjects the flag is not enabled for. It
+	// defaults to Off, or to the first variant.
+	Default string
+	// Overrides pins subjects to variants.
+	Overrides map[string]string
+	// Salt is mixed into the hash of subjects instead of the flag name, so
+	// that changing it reshuffles who is in the rollout.
+	Salt string
+}
+
+// Variant is a value of a variant flag. Subjects are split between the
+// variants of a flag in proportion to their weights.
+type Variant struct {
+	Name   string
+	Weight float64
+}
+
+// Evaluator evaluates flags. It is safe for concurrent use, and flags can be
+// replaced while it is in use. Evaluation is deterministic: the same flag
+// definitions give the same answer for the same subject on every machine.
+type Evaluator struct {
+	state atomic.Pointer[state]
+	// mu serializes the writers of state.
+	mu      sync.Mutex
+	watcher *configx.Watcher[File]
+}
+
+type state struct {
+	flags  map[string]*flag
+	forced map[string]string
+}
+
+type flag struct {
+	Flag
+	allow, deny    map[string]bool
+	variants       []Variant
+	total          float64
+	rolloutSeed    uint64
+	variantSeed    uint64
+	rolloutPercent float64
+}
+
+// New returns an evaluator of flags, which are keyed by name.
+func New(flags map[string]Flag) (*Evaluator, error) {
+	e := &Evaluator{}
+	e.state.Store(&state{})
+	if err := e.Set(flags); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Fixed returns an evaluator for tests that ignores subjects: each flag in
+// variants has the given variant for everyone, and every other flag is
+// disabled.
+func Fixed(variants map[string]string) *Evaluator {
+	e, _ := New(nil)
+	for name, v := range variants {
+		e.Force(name, v)
+	}
+	return e
+}
+
+// Set replaces the flags. If any flag is invalid, the flags are left as
+// they were.
+func (e *Evaluator) Set(flags map[string]Flag) error {
+	compiled, err := compile(flags)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state.Store(&state{flags: compiled, forced: e.state.Load().forced})
+	return nil
+}
+
+// Force pins flag name to variant for every subject, over its definition,
+// until Unforce. A boolean flag is forced with On or Off.
+func (e *Evaluator) Force(name, variant string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s := e.state.Load()
+	forced := make(map[string]string, len(s.forced)+1)
+	for k, v := range s.forced {
+		forced[k] = v
+	}
+	forced[name] = variant
+	e.state.Store(&state{flags: s.flags, forced: forced})
+}
+
+func (e *Evaluator) Unforce(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s := e.state.Load()
+	forced := make(map[string]string, len(s.forced))
+	for k, v := range s.forced {
+		if k != name {
+			forced[k] = v
+		}
+	}
+	e.state.Store(&state{flags: s.flags, forced: forced})
+}
+
+// Enabled reports whether flag name is enabled for subject. Unknown flags
+// are disabled.
+func (e *Evaluator) Enabled(name, subject string) bool {
+	s := e.state.Load()
+	if v, ok := s.forced[name]; ok {
+		f := s.flags[name]
+		return f == nil && v != Off || f != nil && v != f.Default
+	}
+	f := s.flags[name]
+	return f != nil && f.enabled(subject)
+}
+
+// Variant returns the variant of flag name for subject. Unknown flags have
+// the empty variant.
+func (e *Evaluator) Variant(name, subject string) string {
+	s := e.state.Load()
+	if v, ok := s.forced[name]; ok {
+		return v
+	}
+	f := s.flags[name]
+	if f == nil {
+		return ""
+	}
+	if !f.enabled(subject) {
+		return f.Default
+	}
+	if v, ok := f.Overrides[subject]; ok {
+		return v
+	}
+	if len(f.Variants) == 0 {
+		return On
+	}
+	x := bucket(f.variantSeed, subject) * f.total
+	for _, v := range f.variants {
+		if x < v.Weight {
+			return v.Name
+		}
+		x -= v.Weight
+	}
+	return f.variants[len(f.variants)-1].Name
+}
+
+// Flags returns the names of the defined flags in order.
+func (e *Evaluator) Flags() []string {
+	s := e.state.Load()
+	names := make([]string, 0, len(s.flags))
+	for name := range s.flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *flag) enabled(subject string) bool {
+	switch {
+	case !f.Enabled || f.deny[subject]:
+		return false
+	case f.allow[subject]:
+		return true
+	}
+	if _, ok := f.Overrides[subject]; ok {
+		return true
+	}
+	return bucket(f.rolloutSeed, subject)*100 < f.rolloutPercent
+}
+
+// bucket maps subject to [0, 1) uniformly and stably.
+func bucket(seed uint64, subject string) float64 {
+	return float64(hashx.XXHash64String(subject, seed)>>11) / (1 << 53)
+}
+
+func compile(flags map[string]Flag) (map[string]*flag, error) {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiled := make(map[string]*flag, len(flags))
+	for _, name := range names {
+		f, err := compileFlag(name, flags[name])
+		if err != nil {
+			return nil, fmt.Errorf("featurex: flag %q: %w", name, err)
+		}
+		compiled[name] = f
+	}
+	return compiled, nil
+}
+
+func compileFlag(name string, def Flag) (*flag, error) {
+	f := &flag{
+		Flag:           def,
+		allow:          set(def.Allow),
+		deny:           set(def.Deny),
+		rolloutPercent: 100,
+	}
+	if def.Rollout != nil {
+		r := *def.Rollout
+		if math.IsNaN(r) || r < 0 || r > 100 {
+			return nil, fmt.Errorf("%w: rollout %v is not between 0 and 100", ErrInvalidFlag, r)
+		}
+		f.rolloutPercent = r
+	}
+	salt := def.Salt
+	if salt == "" {
+		salt = name
+	}
+	// the rollout and the variant split use independent hashes, so the
+	// subjects added by a growing rollout spread over every variant
+	f.rolloutSeed = hashx.XXHash64String(salt, 0)
+	f.variantSeed = hashx.XXHash64String(salt, 1)
+
+	known := map[string]bool{On: true, Off: true}
+	if len(def.Variants) > 0 {
+		known = make(map[string]bool, len(def.Variants))
+		for _, v := range def.Variants {
+			if known[v.Name] {
+				return nil, fmt.Errorf("%w: duplicate variant %q", ErrInvalidFlag, v.Name)
+			}
+			if math.IsNaN(v.Weight) || v.Weight < 0 || math.IsInf(v.Weight, 0) {
+				return nil, fmt.Errorf("%w: variant %q has weight %v", ErrInvalidFlag, v.Name, v.Weight)
+			}
+			known[v.Name] = true
+			f.total += v.Weight
+			if v.Weight > 0 {
+				f.variants = append(f.variants, v)
+			}
+		}
+		if f.total == 0 {
+			return nil, fmt.Errorf("%w: variants have no weight", ErrInvalidFlag)
+		}
+		if f.Default == "" {
+			f.Default = def.Variants[0].Name
+		}
+	} else if f.Default == "" {
+		f.Default = Off
+	}
+	if !known[f.Default] {
+		return nil, fmt.Errorf("%w: unknown default variant %q", ErrInvalidFlag, f.Default)
+	}
+	for subject, v := range def.Overrides {
+		if !known[v] {
+			return nil, fmt.Errorf("%w: subject %q is overridden to unknown variant %q", ErrInvalidFlag, subject, v)
+		}
+	}
+	return f, nil
+}
+
+func set(s []string) map[string]bool {
+	m := make(map[string]bool, len(s))
+	for _, v := range s {
+		m[v] = true
+	}
+	return m
+}
@@ -0,0 +1,224 @@
+package featurex_test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/unsafe-risk/utilx/configx"
+	"github.com/unsafe-risk/utilx/configx/featurex"
+)
+
+func percent(p float64) *float64 {
+	return &p
+}
+
+func subjects(n int) []string {
+	s := make([]string, n)
+	for i := range s {
+		s[i] = "tenant-" + strconv.Itoa(i)
+	}
+	return s
+}
+
+func TestBoolFlag(t *testing.T) {
+	e, err := featurex.New(map[string]featurex.Flag{
+		"ten":    {Enabled: true, Rollout: percent(10), Allow: []string{"vip"}, Deny: []string{"tenant-1", "vip-denied"}},
+		"twenty": {Enabled: true, Rollout: percent(20), Salt: "ten"},
+		"all":    {Enabled: true},
+		"off":    {Enabled: false, Allow: []string{"vip"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ten, twenty int
+	for _, s := range subjects(20000) {
+		if e.Enabled("ten", s) {
+			ten++
+			if !e.Enabled("twenty", s) {
+				t.Fatalf("%s is in the 10%% rollout but not in the 20%% one with the same salt", s)
+			}
+		}
+		if e.Enabled("twenty", s) {
+			twenty++
+		}
+		if !e.Enabled("all", s) || e.Enabled("off", s) || e.Enabled("unknown", s) {
+			t.Fatalf("%s: wrong answer for a flag without rollout", s)
+		}
+	}
+	if math.Abs(float64(ten)/20000-0.10) > 0.01 || math.Abs(float64(twenty)/20000-0.20) > 0.01 {
+		t.Errorf("rollouts of %d and %d out of 20000", ten, twenty)
+	}
+	if !e.Enabled("ten", "vip") || e.Enabled("ten", "tenant-1") || e.Enabled("ten", "vip-denied") || e.Enabled("off", "vip") {
+		t.Error("allow and deny lists not applied")
+	}
+	if v := e.Variant("ten", "vip"); v != featurex.On {
+		t.Errorf("variant of an enabled boolean flag = %q", v)
+	}
+	if v := e.Variant("off", "vip"); v != featurex.Off {
+		t.Errorf("variant of a disabled boolean flag = %q", v)
+	}
+
+	// answers are stable across evaluators
+	again, _ := featurex.New(map[string]featurex.Flag{"ten": {Enabled: true, Rollout: percent(10)}})
+	for _, s := range subjects(1000)[2:] {
+		if again.Enabled("ten", s) != e.Enabled("ten", s) {
+			t.Fatalf("%s evaluated differently", s)
+		}
+	}
+}
+
+func TestVariantFlag(t *testing.T) {
+	e, err := featurex.New(map[string]featurex.Flag{
+		"search": {
+			Enabled:   true,
+			Rollout:   percent(50),
+			Variants:  []featurex.Variant{{Name: "classic", Weight: 3}, {Name: "vector", Weight: 1}, {Name: "unused"}},
+			Overrides: map[string]string{"tenant-0": "unused"},
+			Deny:      []string{"tenant-1"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for _, s := range subjects(20000)[2:] {
+		counts[e.Variant("search", s)]++
+	}
+	// half are out of the rollout and get the default, the first variant
+	if math.Abs(float64(counts["classic"])/19998-0.875) > 0.015 || math.Abs(float64(counts["vector"])/19998-0.125) > 0.015 || counts["unused"] != 0 {
+		t.Errorf("variant counts %v", counts)
+	}
+	if v := e.Variant("search", "tenant-0"); v != "unused" || !e.Enabled("search", "tenant-0") {
+		t.Errorf("override gave %q", v)
+	}
+	if v := e.Variant("search", "tenant-1"); v != "classic" || e.Enabled("search", "tenant-1") {
+		t.Errorf("denied subject got %q", v)
+	}
+	if v := e.Variant("unknown", "tenant-2"); v != "" {
+		t.Errorf("unknown flag has variant %q", v)
+	}
+}
+
+func TestInvalidFlags(t *testing.T) {
+	for name, f := range map[string]featurex.Flag{
+		"rollout":   {Rollout: percent(101)},
+		"weight":    {Variants: []featurex.Variant{{Name: "a", Weight: -1}}},
+		"no weight": {Variants: []featurex.Variant{{Name: "a"}}},
+		"duplicate": {Variants: []featurex.Variant{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}}},
+		"default":   {Default: "maybe"},
+		"override":  {Variants: []featurex.Variant{{Name: "a", Weight: 1}}, Overrides: map[string]string{"s": "b"}},
+	} {
+		if _, err := featurex.New(map[string]featurex.Flag{name: f}); !errors.Is(err, featurex.ErrInvalidFlag) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+
+	e, _ := featurex.New(map[string]featurex.Flag{"a": {Enabled: true}})
+	if err := e.Set(map[string]featurex.Flag{"a": {Rollout: percent(-1)}}); err == nil || !e.Enabled("a", "s") {
+		t.Errorf("invalid Set: err = %v, or the flags were replaced", err)
+	}
+}
+
+func TestForce(t *testing.T) {
+	e := featurex.Fixed(map[string]string{"a": featurex.On, "b": "v2"})
+	if !e.Enabled("a", "x") || e.Variant("b", "y") != "v2" || !e.Enabled("b", "y") || e.Enabled("c", "z") {
+		t.Error("fixed evaluator")
+	}
+
+	e, _ = featurex.New(map[string]featurex.Flag{"a": {Enabled: true, Deny: []string{"x"}}})
+	e.Force("a", featurex.On)
+	if !e.Enabled("a", "x") {
+		t.Error("forced flag not enabled for a denied subject")
+	}
+	e.Force("a", featurex.Off)
+	if e.Enabled("a", "y") {
+		t.Error("flag forced off is enabled")
+	}
+	e.Unforce("a")
+	if e.Enabled("a", "x") || !e.Enabled("a", "y") {
+		t.Error("unforced flag does not follow its definition")
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	e, _ := featurex.New(map[string]featurex.Flag{"a": {Enabled: true, Rollout: percent(50)}})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, s := range subjects(2000) {
+				e.Enabled("a", s)
+				e.Variant("a", s)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		e.Set(map[string]featurex.Flag{"a": {Enabled: true, Rollout: percent(float64(i))}})
+		e.Force("b", featurex.On)
+	}
+	wg.Wait()
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	write := func(content string) {
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"flags": {"beta": {"enabled": true, "allow": ["t1"], "rollout": 0}}}`)
+
+	if _, err := featurex.Load(filepath.Join(t.TempDir(), "none.json")); err == nil {
+		t.Error("missing file loaded")
+	}
+	static, err := featurex.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 10)
+	e, err := featurex.Watch(path, configx.WithDebounce(10*time.Millisecond), configx.WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if !e.Enabled("beta", "t1") || e.Enabled("beta", "t2") || !static.Enabled("beta", "t1") {
+		t.Fatal("flags not loaded")
+	}
+
+	write(`{"flags": {"beta": {"enabled": true, "rollout": 100}}}`)
+	deadline := time.Now().Add(5 * time.Second)
+	for !e.Enabled("beta", "t2") {
+		if time.Now().After(deadline) {
+			t.Fatal("flags not reloaded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	write(`{"flags": {"beta": {"enabled": true, "rollout": 200}}}`)
+	select {
+	case err := <-errs:
+		if !errors.Is(err, featurex.ErrInvalidFlag) {
+			t.Errorf("reload error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid flags not reported")
+	}
+	if !e.Enabled("beta", "t2") || e.Flags()[0] != "beta" {
+		t.Error("invalid flags replaced the valid ones")
+	}
+}
@@ -0,0 +1,69 @@
+package featurex
+
+import (
+	"github.com/unsafe-risk/utilx/configx"
+)
+
+// File is the JSON document flags are loaded from:
+//
+//	{
+//		"flags": {
+//			"new-checkout": {"enabled": true, "rollout": 25, "deny": ["tenant-9"]},
+//			"search": {
+//				"enabled": true,
+//				"variants": [{"name": "classic", "weight": 80}, {"name": "vector", "weight": 20}],
+//				"overrides": {"tenant-1": "vector"}
+//			}
+//		}
+//	}
+type File struct {
+	Flags map[string]Flag
+}
+
+func (f *File) Validate() error {
+	_, err := compile(f.Flags)
+	return err
+}
+
+// Load returns an evaluator of the flags defined in the JSON file at path.
+func Load(path string) (*Evaluator, error) {
+	var f File
+	if _, err := configx.Load(&f, configx.WithFiles(path)); err != nil {
+		return nil, err
+	}
+	return New(f.Flags)
+}
+
+// Watch is Load that keeps the evaluator up to date with the file. Edits
+// that make the file invalid are reported to the error handler of the
+// options, if any, and leave the flags as they were. Close stops watching.
+func Watch(path string, opts ...configx.WatchOption) (*Evaluator, error) {
+	loader, err := configx.NewLoader(configx.WithFiles(path))
+	if err != nil {
+		return nil, err
+	}
+	w, err := configx.NewWatcher[File](loader, opts...)
+	if err != nil {
+		return nil, err
+	}
+	e, err := New(w.Current().Flags)
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+	e.watcher = w
+	// Observe also catches up with a reload that came after New
+	w.Observe(func(_, new *File) {
+		// the watcher validated the flags already
+		e.Set(new.Flags)
+	})
+	return e, nil
+}
+
+// Close stops watching the file of an evaluator made by Watch.
+func (e *Evaluator) Close() error {
+	if e.watcher == nil {
+		return nil
+	}
+	return e.watcher.Close()
+}
@@ -0,0 +1,260 @@
+package configx
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// structField is an exported field of a struct as seen by the loader.
+type structField struct {
+	index int
+	name  string
+	// key is the normalized key the field is looked up by in files.
+	key string
+	// inline is set for embedded structs without a name, whose fields are
+	// promoted like in encoding/json.
+	inline bool
+
+	env, flag, def, usage string
+	hasDef, required      bool
+	noEnv, noFlag         bool
+	// envExact is set when env comes from an env tag, which names the
+	// variable in full.
+	envExact bool
+}
+
+var structFieldsCache sync.Map // reflect.Type -> []structField
+
+// structFields lists the fields of struct type t that take part in loading,
+// with their tags parsed. See Load for the tags.
+func structFields(t reflect.Type) []structField {
+	if fs, ok := structFieldsCache.Load(t); ok {
+		return fs.([]structField)
+	}
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("config")
+		if tag == "-" || !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		f := structField{
+			index: i,
+			name:  sf.Name,
+			usage: sf.Tag.Get("usage"),
+		}
+		f.def, f.hasDef = sf.Tag.Lookup("default")
+		for _, opt := range strings.Split(opts, ",") {
+			if opt == "required" {
+				f.required = true
+			}
+		}
+
+		ft := indirect(sf.Type)
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && !textual(ft) {
+			f.inline = true
+		} else if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = snake(sf.Name)
+		}
+		f.key = normalize(name)
+		switch env := sf.Tag.Get("env"); env {
+		case "-":
+			f.noEnv = true
+		case "":
+			f.env = strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		default:
+			f.env = env
+			f.envExact = true
+		}
+		switch flag := sf.Tag.Get("flag"); flag {
+		case "-":
+			f.noFlag = true
+		case "":
+			f.flag = strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+		default:
+			f.flag = flag
+		}
+		fields = append(fields, f)
+	}
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+// leaf is a field set from a single string by defaults, environment
+// variables and flags.
+type leaf struct {
+	// path is the dotted Go path of the field, such as "DB.Host".
+	path string
+	// index walks from the root struct to the field, through pointers.
+	index []int
+	typ   reflect.Type
+	// env is the environment variable name without the prefix, unless
+	// envExact is set. It is empty if the field cannot be set from the
+	// environment, and so is flag for flags.
+	env, flag        string
+	envExact         bool
+	def, usage       string
+	hasDef, required bool
+}
+
+var leavesCache sync.Map // reflect.Type -> []*leaf
+
+// leaves lists the fields of struct type t that hold values, recursing into
+// nested structs. Slices of structs are leaves too, but only files can set
+// them.
+func leaves(t reflect.Type) []*leaf {
+	if ls, ok := leavesCache.Load(t); ok {
+		return ls.([]*leaf)
+	}
+	var ls []*leaf
+	collectLeaves(&ls, t, &leaf{}, map[reflect.Type]bool{t: true})
+	leavesCache.Store(t, ls)
+	return ls
+}
+
+// collectLeaves appends the leaves of struct type t, whose own names are
+// given by parent.
+func collectLeaves(ls *[]*leaf, t reflect.Type, parent *leaf, visiting map[reflect.Type]bool) {
+	for _, f := range structFields(t) {
+		ft := t.Field(f.index).Type
+		l := &leaf{
+			path:     parent.path,
+			index:    append(parent.index[:len(parent.index):len(parent.index)], f.index),
+			typ:      ft,
+			env:      parent.env,
+			flag:     parent.flag,
+			envExact: parent.envExact,
+			def:      f.def,
+			usage:    f.usage,
+			hasDef:   f.hasDef,
+			required: f.required,
+		}
+		if !f.inline {
+			l.path = join(l.path, ".", f.name)
+			switch {
+			case f.noEnv || parent.path != "" && parent.env == "":
+				l.env = ""
+			case f.envExact:
+				l.env, l.envExact = f.env, true
+			default:
+				l.env = join(l.env, "_", f.env)
+			}
+			if f.noFlag || parent.path != "" && parent.flag == "" {
+				l.flag = ""
+			} else {
+				l.flag = join(l.flag, ".", f.flag)
+			}
+		}
+
+		if st := indirect(ft); st.Kind() == reflect.Struct && !textual(st) {
+			// a type containing itself through a pointer is only followed once
+			if !visiting[st] {
+				visiting[st] = true
+				collectLeaves(ls, st, l, visiting)
+				delete(visiting, st)
+			}
+			continue
+		}
+		if !textual(ft) {
+			l.env, l.flag = "", ""
+		}
+		*ls = append(*ls, l)
+	}
+}
+
+func join(prefix, sep, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + sep + name
+}
+
+func (l *leaf) value(root reflect.Value) reflect.Value {
+	v := root
+	for _, i := range l.index {
+		v = alloc(v).Field(i)
+	}
+	return v
+}
+
+// alloc dereferences v, allocating nil pointers on the way.
+func alloc(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// textual reports whether a value of type t can be parsed from a string.
+func textual(t reflect.Type) bool {
+	t = indirect(t)
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return textual(t.Elem())
+	}
+	return false
+}
+
+// snake converts a Go identifier to snake case: "MaxIdleConns" becomes
+// "max_idle_conns" and "HTTPPort" becomes "http_port".
+func snake(name string) string {
+	rs := []rune(name)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(rs[i-1]) || unicode.IsDigit(rs[i-1]) ||
+				i+1 < len(rs) && unicode.IsLower(rs[i+1]) && unicode.IsUpper(rs[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalize folds the spellings of a key, so "max_conns", "max-conns",
+// "maxConns" and "MaxConns" all match.
+func normalize(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		if r == '_' || r == '-' {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,139 @@
+package configx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownFormat = errors.New("unknown config file format")
+
+// formats maps file extensions to decoders. Every decoder produces nested
+// map[string]any and []any values.
+var formats = map[string]func([]byte) (map[string]any, error){
+	".json": decodeJSON,
+	".toml": decodeTOML,
+	".yaml": decodeYAML,
+	".yml":  decodeYAML,
+}
+
+func decoderFor(path string) (func([]byte) (map[string]any, error), error) {
+	decode, ok := formats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+	}
+	return decode, nil
+}
+
+func readFile(path string) (map[string]any, error) {
+	decode, err := decoderFor(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+func decodeJSON(data []byte) (map[string]any, error) {
+	var m map[string]any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func decodeTOML(data []byte) (map[string]any, error) {
+	var m map[string]any
+	if err := toml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return normalizeTOML(m).(map[string]any), nil
+}
+
+// normalizeTOML turns arrays of tables into []any and local dates and times,
+// which have no time zone, back into strings.
+func normalizeTOML(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if v == nil {
+			return map[string]any{}
+		}
+		for k, e := range v {
+			v[k] = normalizeTOML(e)
+		}
+	case []map[string]any:
+		items := make([]any, len(v))
+		for i, e := range v {
+			items[i] = normalizeTOML(e)
+		}
+		return items
+	case []any:
+		for i, e := range v {
+			v[i] = normalizeTOML(e)
+		}
+	case time.Time:
+		if layout, ok := tomlLocalLayouts[v.Location().String()]; ok {
+			return v.Format(layout)
+		}
+	}
+	return v
+}
+
+// tomlLocalLayouts are the layouts of local dates and times by the name of
+// the zone the toml package gives them.
+var tomlLocalLayouts = map[string]string{
+	"datetime-local": "2006-01-02T15:04:05.999999999",
+	"date-local":     "2006-01-02",
+	"time-local":     "15:04:05.999999999",
+}
+
+func decodeYAML(data []byte) (map[string]any, error) {
+	var m map[string]any
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return normalizeYAML(m).(map[string]any), nil
+}
+
+// normalizeYAML turns the map[any]any that YAML produces for mappings with
+// non-string keys into map[string]any.
+func normalizeYAML(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if v == nil {
+			return map[string]any{}
+		}
+		for k, e := range v {
+			v[k] = normalizeYAML(e)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalizeYAML(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = normalizeYAML(e)
+		}
+	}
+	return v
+}
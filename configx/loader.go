@@ -0,0 +1,494 @@
+package configx
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/unsafe-risk/utilx/configx/optionalx"
+)
+
+// Source is a layer of configuration. Later sources override earlier ones.
+type Source uint8
+
+const (
+	SourceNone Source = iota
+	SourceDefault
+	SourceFile
+	SourceEnv
+	SourceFlag
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	}
+	return "none"
+}
+
+// Origin tells where the value of a field came from.
+type Origin struct {
+	Source Source
+	// Name is the file, environment variable or flag that set the field.
+	Name string
+}
+
+func (o Origin) String() string {
+	switch o.Source {
+	case SourceNone, SourceDefault:
+		return o.Source.String()
+	case SourceFlag:
+		return "flag -" + o.Name
+	}
+	return o.Source.String() + " " + o.Name
+}
+
+// Report describes a load.
+type Report struct {
+	// Origins maps the dotted path of each field that was set, such as
+	// "DB.Host", to where its value came from.
+	Origins map[string]Origin
+	// Args are the arguments left after the flags.
+	Args []string
+}
+
+// Origin returns the origin of the field at path, which is the zero Origin
+// if no source set it.
+func (r *Report) Origin(path string) Origin {
+	return r.Origins[path]
+}
+
+// String lists the fields that were set with their origins, one per line.
+func (r *Report) String() string {
+	paths := make([]string, 0, len(r.Origins))
+	for p := range r.Origins {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	for _, p := range paths {
+		fmt.Fprintf(w, "%s\t%s\n", p, r.Origins[p])
+	}
+	w.Flush()
+	return b.String()
+}
+
+var (
+	ErrMissing       = errors.New("missing required field")
+	ErrInvalidTarget = errors.New("load target must be a non-nil pointer to a struct")
+)
+
+// FieldError is a field that could not be set, or a required field that no
+// source set, in which case Err is ErrMissing.
+type FieldError struct {
+	Field  string
+	Origin Origin
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	if e.Origin.Source == SourceNone {
+		return e.Field + ": " + e.Err.Error()
+	}
+	return e.Field + " (" + e.Origin.String() + "): " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Error is the error of a load. It lists every missing required field and
+// every other problem. errors.Is and errors.As match any of them.
+type Error struct {
+	Errors []error
+}
+
+// Missing returns the paths of the required fields that no source set.
+func (e *Error) Missing() []string {
+	var paths []string
+	for _, err := range e.Errors {
+		if fe, ok := err.(*FieldError); ok && fe.Err == ErrMissing {
+			paths = append(paths, fe.Field)
+		}
+	}
+	return paths
+}
+
+func (e *Error) Error() string {
+	var msgs []string
+	if missing := e.Missing(); len(missing) > 0 {
+		msgs = append(msgs, "missing required fields: "+strings.Join(missing, ", "))
+	}
+	for _, err := range e.Errors {
+		if fe, ok := err.(*FieldError); !ok || fe.Err != ErrMissing {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	return "configx: " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the errors matches target.
+func (e *Error) Is(target error) bool {
+	return (*optionalx.Error)(e).Is(target)
+}
+
+// As sets target to the first of the errors that matches it.
+func (e *Error) As(target any) bool {
+	return (*optionalx.Error)(e).As(target)
+}
+
+// Options selects the sources of a Loader.
+type Options struct {
+	// Files are read in order, each overriding the ones before. The format
+	// follows the extension: .json, .toml, .yaml or .yml.
+	Files []string
+	// Env enables environment variables named EnvPrefix_FIELD_PATH, such as
+	// APP_DB_HOST for the field DB.Host with the prefix APP.
+	Env       bool
+	EnvPrefix string
+	// LookupEnv reads the environment. It defaults to os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+	// Args are parsed as flags named after the field path, such as -db.host,
+	// unless nil.
+	Args []string
+	// Name and Output are the name and the help output of the flag set.
+	Name   string
+	Output io.Writer
+	// SecretsDir holds a file for each Secret field, named after its
+	// environment variable without the prefix in lower case, such as
+	// db_password for DB.Password.
+	SecretsDir string
+	// SecretKey decrypts the secrets encrypted with EncryptSecret.
+	SecretKey []byte
+}
+
+type Option = optionalx.Option[Options]
+
+func defaultOptions() Options {
+	return Options{
+		LookupEnv: os.LookupEnv,
+		Name:      filepath.Base(os.Args[0]),
+	}
+}
+
+// WithFiles adds config files. Each must have a known extension.
+func WithFiles(paths ...string) Option {
+	return func(o *Options) error {
+		for _, p := range paths {
+			if _, err := decoderFor(p); err != nil {
+				return err
+			}
+		}
+		o.Files = append(o.Files, paths...)
+		return nil
+	}
+}
+
+// WithEnv enables environment variables with the given prefix. An empty
+// prefix is allowed, but lets any variable whose name matches a field, such
+// as PATH, set it.
+func WithEnv(prefix string) Option {
+	return optionalx.Func(func(o *Options) {
+		o.Env = true
+		o.EnvPrefix = strings.TrimSuffix(prefix, "_")
+	})
+}
+
+// WithLookupEnv reads the environment with lookup, which suits tests.
+func WithLookupEnv(lookup func(key string) (string, bool)) Option {
+	return optionalx.Func(func(o *Options) {
+		o.LookupEnv = lookup
+	})
+}
+
+// WithArgs parses args, usually os.Args[1:], as flags.
+func WithArgs(args []string) Option {
+	return optionalx.Func(func(o *Options) {
+		if args == nil {
+			args = []string{}
+		}
+		o.Args = args
+	})
+}
+
+// WithFlagOutput sets where the usage of the flags is written on -help and
+// on errors.
+func WithFlagOutput(name string, output io.Writer) Option {
+	return optionalx.Func(func(o *Options) {
+		o.Name = name
+		o.Output = output
+	})
+}
+
+// WithSecretsDir reads Secret fields from the files of dir, such as
+// /run/secrets. The files are read after the config files and before the
+// environment.
+func WithSecretsDir(dir string) Option {
+	return optionalx.Func(func(o *Options) {
+		o.SecretsDir = dir
+	})
+}
+
+// WithSecretKey decrypts encrypted Secret values with key.
+func WithSecretKey(key []byte) Option {
+	return func(o *Options) error {
+		if len(key) == 0 {
+			return ErrNoSecretKey
+		}
+		o.SecretKey = key
+		return nil
+	}
+}
+
+// Loader loads structs from layered sources. In order of precedence, from
+// lowest to highest, fields are set from
+//
+//   - the default struct tag,
+//   - the config files,
+//   - the secrets directory,
+//   - environment variables, or the files named by NAME_FILE variables,
+//   - command-line flags.
+//
+// Values are converted with convx; durations are parsed with
+// time.ParseDuration and types implementing encoding.TextUnmarshaler parse
+// themselves. Slices are read from arrays in files and from comma separated
+// lists elsewhere. See the tags understood in the documentation of Load.
+type Loader struct {
+	options Options
+}
+
+func NewLoader(opts ...Option) (*Loader, error) {
+	options, err := optionalx.New(defaultOptions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Loader{options: options}, nil
+}
+
+// Load is NewLoader(opts...).Load(dst).
+//
+// Fields are configured with struct tags:
+//
+//	config:"name,required"  the key in files, and the base of the env and
+//	                        flag names; "-" skips the field
+//	default:"value"         the value when no source sets it
+//	env:"NAME"              the full environment variable name; "-" disables it
+//	flag:"name"             the flag name; "-" disables it
+//	usage:"text"            the help text of the flag
+//
+// Without a config tag, the key is the field name in snake case. Keys in
+// files match regardless of case, dashes and underscores.
+func Load(dst any, opts ...Option) (*Report, error) {
+	l, err := NewLoader(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return l.Load(dst)
+}
+
+// Load sets the fields of dst, a pointer to a struct, from every source. It
+// returns an *Error listing every field that could not be set and every
+// missing required field. If *dst implements optionalx.Validator, it is
+// validated once everything else succeeded. A dst that is not a non-nil
+// pointer to a struct gives an error wrapping ErrInvalidTarget.
+func (l *Loader) Load(dst any) (*Report, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("configx: %w, not %v", ErrInvalidTarget, reflect.TypeOf(dst))
+	}
+	root := rv.Elem()
+	ls := leaves(root.Type())
+	report := &Report{Origins: make(map[string]Origin)}
+	var errs []error
+
+	set := func(leaf *leaf, raw any, origin Origin) {
+		s := &setter{origin: origin, origins: report.Origins, key: l.options.SecretKey}
+		if err := s.assign(leaf.value(root), raw, leaf.path); err != nil {
+			errs = append(errs, &FieldError{Field: leaf.path, Origin: origin, Err: err})
+		}
+	}
+
+	for _, leaf := range ls {
+		if leaf.hasDef {
+			set(leaf, leaf.def, Origin{Source: SourceDefault})
+		}
+	}
+
+	for _, path := range l.options.Files {
+		m, err := readFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s := &setter{origin: Origin{Source: SourceFile, Name: path}, origins: report.Origins, key: l.options.SecretKey}
+		s.assignStruct(root, m, "")
+		errs = append(errs, s.errs...)
+	}
+
+	if l.options.SecretsDir != "" {
+		for _, leaf := range ls {
+			if leaf.env == "" || !isSecret(leaf.typ) {
+				continue
+			}
+			path := filepath.Join(l.options.SecretsDir, strings.ToLower(leaf.env))
+			text, err := readSecretFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			origin := Origin{Source: SourceFile, Name: path}
+			if err != nil {
+				errs = append(errs, &FieldError{Field: leaf.path, Origin: origin, Err: err})
+				continue
+			}
+			set(leaf, text, origin)
+		}
+	}
+
+	if l.options.Env {
+		for _, leaf := range ls {
+			name := l.envName(leaf)
+			if name == "" {
+				continue
+			}
+			text, ok := l.options.LookupEnv(name)
+			path, fromFile := l.options.LookupEnv(name + "_FILE")
+			switch {
+			case ok && fromFile:
+				errs = append(errs, &FieldError{Field: leaf.path, Err: fmt.Errorf("both %s and %s_FILE are set", name, name)})
+			case ok:
+				set(leaf, text, Origin{Source: SourceEnv, Name: name})
+			case fromFile:
+				origin := Origin{Source: SourceEnv, Name: name + "_FILE"}
+				text, err := readSecretFile(path)
+				if err != nil {
+					errs = append(errs, &FieldError{Field: leaf.path, Origin: origin, Err: err})
+					continue
+				}
+				set(leaf, text, origin)
+			}
+		}
+	}
+
+	if l.options.Args != nil {
+		args, err := l.parseFlags(ls, set)
+		if err != nil {
+			return report, err
+		}
+		report.Args = args
+	}
+
+	for _, leaf := range ls {
+		if _, ok := report.Origins[leaf.path]; leaf.required && !ok {
+			errs = append(errs, &FieldError{Field: leaf.path, Err: ErrMissing})
+		}
+	}
+	if len(errs) == 0 {
+		if v, ok := dst.(optionalx.Validator); ok {
+			if err := v.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return report, &Error{Errors: errs}
+	}
+	return report, nil
+}
+
+// readSecretFile reads a value from a file, without the line ending that
+// editors and echo leave at its end.
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
+
+func (l *Loader) envName(leaf *leaf) string {
+	if leaf.env == "" || leaf.envExact || l.options.EnvPrefix == "" {
+		return leaf.env
+	}
+	return l.options.EnvPrefix + "_" + leaf.env
+}
+
+// parseFlags registers a flag for every leaf that has one and sets the
+// fields of the flags given in the arguments. Errors of the flag package,
+// including flag.ErrHelp, are returned as they are.
+func (l *Loader) parseFlags(ls []*leaf, set func(*leaf, any, Origin)) ([]string, error) {
+	fs := flag.NewFlagSet(l.options.Name, flag.ContinueOnError)
+	fs.SetOutput(l.options.Output)
+	for _, leaf := range ls {
+		if leaf.flag != "" {
+			fs.Var(&flagValue{leaf: leaf}, leaf.flag, leaf.usage)
+		}
+	}
+	if err := fs.Parse(l.options.Args); err != nil {
+		return nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		fv := f.Value.(*flagValue)
+		origin := Origin{Source: SourceFlag, Name: f.Name}
+		if !fv.list() {
+			set(fv.leaf, fv.values[0], origin)
+			return
+		}
+		// each flag is one element, commas included
+		items := make([]any, len(fv.values))
+		for i, v := range fv.values {
+			items[i] = v
+		}
+		set(fv.leaf, items, origin)
+	})
+	return fs.Args(), nil
+}
+
+// flagValue collects the values of a flag. A repeated flag replaces a
+// scalar and appends to a slice, other than a []byte.
+type flagValue struct {
+	leaf   *leaf
+	values []string
+}
+
+func (f *flagValue) String() string {
+	if f == nil || f.leaf == nil || !f.leaf.hasDef {
+		return ""
+	}
+	if isSecret(f.leaf.typ) {
+		return Redacted
+	}
+	return f.leaf.def
+}
+
+func (f *flagValue) Set(s string) error {
+	if f.list() {
+		f.values = append(f.values, s)
+	} else {
+		f.values = []string{s}
+	}
+	return nil
+}
+
+func (f *flagValue) list() bool {
+	t := indirect(f.leaf.typ)
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return indirect(f.leaf.typ).Kind() == reflect.Bool
+}
@@ -0,0 +1,87 @@
+package configx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// inotify watches the directories of the files rather than the files, so
+// that files replaced by a rename, as editors and Kubernetes volumes do,
+// keep being watched.
+type inotify struct {
+	f      *os.File
+	events chan struct{}
+	once   sync.Once
+}
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MODIFY | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+func newInotify(files []string) (notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	// a non-blocking descriptor is served by the runtime poller, so Close
+	// interrupts a pending Read
+	n := &inotify{
+		f:      os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan struct{}, 1),
+	}
+
+	names := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		names[filepath.Base(file)] = true
+		dirs[filepath.Dir(file)] = true
+	}
+	for dir := range dirs {
+		if _, err := syscall.InotifyAddWatch(fd, dir, inotifyMask); err != nil {
+			n.f.Close()
+			return nil, os.NewSyscallError("inotify_add_watch", err)
+		}
+	}
+	go n.read(names)
+	return n, nil
+}
+
+func (n *inotify) read(names map[string]bool) {
+	defer close(n.events)
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		size, err := n.f.Read(buf)
+		if err != nil {
+			return
+		}
+		changed := false
+		for off := 0; off+syscall.SizeofInotifyEvent <= size; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			name := buf[off+syscall.SizeofInotifyEvent : off+syscall.SizeofInotifyEvent+int(ev.Len)]
+			off += syscall.SizeofInotifyEvent + int(ev.Len)
+			// names are padded with NULs; Kubernetes swaps a ..data symlink
+			s := strings.TrimRight(string(name), "\x00")
+			if names[s] || strings.HasPrefix(s, "..") || ev.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				changed = true
+			}
+		}
+		if changed {
+			signal(n.events)
+		}
+	}
+}
+
+func (n *inotify) Events() <-chan struct{} {
+	return n.events
+}
+
+func (n *inotify) Close() error {
+	var err error
+	n.once.Do(func() {
+		err = n.f.Close()
+	})
+	return err
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package configx
+
+import "errors"
+
+func newInotify(files []string) (notifier, error) {
+	return nil, errors.New("inotify is only available on linux")
+}
@@ -1,5 +1,113 @@
+// Package optionalx builds values from optional parameters.
 package optionalx
 
+import (
+	"errors"
+	"strings"
+)
+
+// Option sets one or more fields of a T. Options see the defaults and the
+// changes of every option before them. A failing option should leave t as it
+// was and return an error that names the offending setting.
+type Option[T any] func(t *T) error
+
+// Func adapts a setter that cannot fail.
+func Func[T any](f func(t *T)) Option[T] {
+	return func(t *T) error {
+		f(t)
+		return nil
+	}
+}
+
+// Validator is implemented by configurations that check themselves once every
+// option has been applied.
+type Validator interface {
+	Validate() error
+}
+
+// New starts from defaults, or the zero value if defaults is nil, applies
+// opts in order and then runs the validation of *T if it implements
+// Validator. Every option runs even if an earlier one failed, so the returned
+// *Error lists all of the problems at once. On error the partial value is
+// returned as well.
+func New[T any](defaults func() T, opts ...Option[T]) (T, error) {
+	var t T
+	if defaults != nil {
+		t = defaults()
+	}
+	var errs []error
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(&t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if v, ok := any(&t).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return t, &Error{Errors: errs}
+	}
+	return t, nil
+}
+
+// Must is New that panics on error, for configurations built from constants.
+func Must[T any](defaults func() T, opts ...Option[T]) T {
+	t, err := New(defaults, opts...)
+	if err != nil {
+		panic("optionalx: " + err.Error())
+	}
+	return t
+}
+
+// Error is the aggregated error of New. errors.Is and errors.As match any of
+// its errors.
+type Error struct {
+	Errors []error
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	var b strings.Builder
+	b.WriteString("invalid options: ")
+	for i, err := range e.Errors {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Is reports whether any of the errors matches target.
+func (e *Error) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As sets target to the first of the errors that matches it.
+func (e *Error) As(target any) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parameter applies funcs to a nil *T and passes the result to constructor.
+//
+// Deprecated: options cannot see defaults or fail; use New.
 func Parameter[T, R any](constructor func(*T) *R, funcs ...func(*T) *T) *R {
 	var p *T
 	for _, f := range funcs {
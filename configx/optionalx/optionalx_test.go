@@ -0,0 +1,120 @@
+package optionalx_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/configx/optionalx"
+)
+
+var (
+	errNegative = errors.New("size must not be negative")
+	errEmpty    = errors.New("name must not be empty")
+	errTooSmall = errors.New("size must fit the name")
+)
+
+type config struct {
+	name string
+	size int
+}
+
+func (c *config) Validate() error {
+	if c.size < len(c.name) {
+		return errTooSmall
+	}
+	return nil
+}
+
+func defaults() config {
+	return config{name: "pool", size: 8}
+}
+
+func withSize(n int) optionalx.Option[config] {
+	return func(c *config) error {
+		if n < 0 {
+			return errNegative
+		}
+		c.size = n
+		return nil
+	}
+}
+
+func withName(name string) optionalx.Option[config] {
+	return func(c *config) error {
+		if name == "" {
+			return errEmpty
+		}
+		c.name = name
+		return nil
+	}
+}
+
+func TestNew(t *testing.T) {
+	c, err := optionalx.New(defaults)
+	if err != nil || c != defaults() {
+		t.Fatalf("New() = %+v, %v, want defaults", c, err)
+	}
+
+	var doubled int
+	c, err = optionalx.New(defaults, withSize(16), nil, optionalx.Func(func(c *config) {
+		doubled = c.size * 2
+	}))
+	if err != nil || c.size != 16 || c.name != "pool" || doubled != 32 {
+		t.Fatalf("New(withSize(16)) = %+v, %v, doubled %d", c, err, doubled)
+	}
+
+	c, err = optionalx.New(nil, withName("x"))
+	if !errors.Is(err, errTooSmall) || c.name != "x" {
+		t.Fatalf("New(nil, withName) = %+v, %v, want validation error", c, err)
+	}
+}
+
+type sizeError struct{ size int }
+
+func (e *sizeError) Error() string { return "bad size " + strconv.Itoa(e.size) }
+
+func TestNewAggregatesErrors(t *testing.T) {
+	_, err := optionalx.New(defaults, withSize(-1), withName(""), withSize(2), withName("longer"))
+	var oe *optionalx.Error
+	if !errors.As(err, &oe) {
+		t.Fatalf("err = %v, want *optionalx.Error", err)
+	}
+	want := []error{errNegative, errEmpty, errTooSmall}
+	if len(oe.Errors) != len(want) {
+		t.Fatalf("errors = %v, want %v", oe.Errors, want)
+	}
+	for i := range want {
+		if oe.Errors[i] != want[i] {
+			t.Errorf("errors[%d] = %v, want %v", i, oe.Errors[i], want[i])
+		}
+		if !errors.Is(err, want[i]) {
+			t.Errorf("errors.Is(err, %v) = false", want[i])
+		}
+	}
+	if got := err.Error(); got != "invalid options: size must not be negative; name must not be empty; size must fit the name" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	_, err = optionalx.New(defaults, withSize(4), func(c *config) error {
+		return &sizeError{size: c.size}
+	})
+	var se *sizeError
+	if !errors.As(err, &se) || se.size != 4 {
+		t.Errorf("errors.As(err, *sizeError) = %v, %+v", errors.As(err, &se), se)
+	}
+
+	_, err = optionalx.New(defaults, withSize(-1))
+	if err.Error() != errNegative.Error() {
+		t.Errorf("single error: Error() = %q", err.Error())
+	}
+}
+
+func TestMust(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Must did not panic on an invalid option")
+		}
+	}()
+	optionalx.Must(defaults, withSize(-1))
+}
@@ -0,0 +1,130 @@
+package configx
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/unsafe-risk/utilx/cryptox/aesx"
+)
+
+const (
+	// Redacted is how a Secret prints.
+	Redacted = "***"
+	// EncryptedPrefix marks a secret value encrypted with EncryptSecret.
+	EncryptedPrefix = "aes:"
+)
+
+var ErrNoSecretKey = errors.New("encrypted secret but no secret key")
+
+// Secret holds a configuration value that must not leak. It prints as ***
+// with every fmt verb and marshals to "***" in JSON, text and YAML, so
+// configuration dumps and logs do not reveal it. Value returns the value.
+//
+// Loaders read secrets like other fields, and also from files: an
+// environment variable NAME_FILE names a file holding the value of NAME,
+// and WithSecretsDir reads secrets from a directory of files, as Docker and
+// Kubernetes mount them. A value starting with EncryptedPrefix is decrypted
+// with the key given by WithSecretKey.
+type Secret[T any] struct {
+	value T
+}
+
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+func (s Secret[T]) Value() T {
+	return s.value
+}
+
+func (Secret[T]) String() string {
+	return Redacted
+}
+
+func (Secret[T]) GoString() string {
+	return Redacted
+}
+
+func (Secret[T]) Format(f fmt.State, verb rune) {
+	io.WriteString(f, Redacted)
+}
+
+func (Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+func (Secret[T]) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + Redacted + `"`), nil
+}
+
+func (Secret[T]) MarshalYAML() (any, error) {
+	return Redacted, nil
+}
+
+// UnmarshalText parses text as a T, the way loaders parse fields.
+func (s *Secret[T]) UnmarshalText(text []byte) error {
+	return s.setSecret(string(text), nil)
+}
+
+func (s *Secret[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.value)
+}
+
+func (s *Secret[T]) setSecret(raw any, key []byte) error {
+	if text, ok := raw.(string); ok && strings.HasPrefix(text, EncryptedPrefix) {
+		plain, err := decryptSecret(text, key)
+		if err != nil {
+			return err
+		}
+		raw = plain
+	}
+	var value T
+	if err := (&setter{}).assign(reflect.ValueOf(&value).Elem(), raw, ""); err != nil {
+		// the error may quote the value
+		return fmt.Errorf("invalid %v", reflect.TypeOf(*s))
+	}
+	s.value = value
+	return nil
+}
+
+// secretSetter is implemented by every *Secret[T].
+type secretSetter interface {
+	setSecret(raw any, key []byte) error
+}
+
+var secretSetterType = reflect.TypeOf((*secretSetter)(nil)).Elem()
+
+func isSecret(t reflect.Type) bool {
+	return reflect.PointerTo(indirect(t)).Implements(secretSetterType)
+}
+
+// EncryptSecret seals plaintext under key into a value that loaders given the
+// same key with WithSecretKey decrypt into a Secret. Values encrypted with
+// aesx.EncryptGCM, as earlier versions did, are still decrypted.
+func EncryptSecret(plaintext string, key []byte) (string, error) {
+	blob, err := aesx.Seal([]byte(plaintext), key)
+	if err != nil {
+		return "", err
+	}
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(blob), nil
+}
+
+func decryptSecret(text string, key []byte) (string, error) {
+	if key == nil {
+		return "", ErrNoSecretKey
+	}
+	blob, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(text, EncryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("encrypted secret: %w", err)
+	}
+	plain, err := aesx.Open(blob, aesx.Keyring{"": key})
+	if err != nil {
+		return "", fmt.Errorf("encrypted secret: %w", err)
+	}
+	return string(plain), nil
+}
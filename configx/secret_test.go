@@ -0,0 +1,113 @@
+package configx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/unsafe-risk/utilx/configx"
+)
+
+type credentials struct {
+	User     string
+	Password configx.Secret[string]
+	PIN      configx.Secret[int]
+	Keys     configx.Secret[[]string]
+}
+
+func TestSecretRedacted(t *testing.T) {
+	c := credentials{User: "admin", Password: configx.NewSecret("hunter2"), PIN: configx.NewSecret(1234)}
+
+	var logged bytes.Buffer
+	log.New(&logged, "", 0).Printf("%v %+v %#v %s %q %x %d", c, c, c, c.Password, c.Password, c.Password, c.PIN)
+	js, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ym, err := yaml.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, out := range map[string]string{"fmt": logged.String(), "json": string(js), "yaml": string(ym)} {
+		if strings.Contains(out, "hunter2") || strings.Contains(out, "1234") || !strings.Contains(out, configx.Redacted) {
+			t.Errorf("%s output leaks the secret or does not redact it: %s", name, out)
+		}
+	}
+	if c.Password.Value() != "hunter2" || c.PIN.Value() != 1234 {
+		t.Errorf("values %q %d", c.Password.Value(), c.PIN.Value())
+	}
+
+	var back credentials
+	if err := json.Unmarshal([]byte(`{"Password": "s3cret", "PIN": 42}`), &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Password.Value() != "s3cret" || back.PIN.Value() != 42 {
+		t.Errorf("unmarshalled %q %d", back.Password.Value(), back.PIN.Value())
+	}
+}
+
+func TestSecretLoad(t *testing.T) {
+	dir := t.TempDir()
+	key := []byte("master key")
+	encrypted, err := configx.EncryptSecret("from-file", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := writeFile(t, "config.yaml", "user: admin\npassword: "+encrypted+"\npin: 1\n")
+	pinFile := filepath.Join(dir, "pin")
+	if err := os.WriteFile(pinFile, []byte("4321\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	secrets := filepath.Join(dir, "secrets")
+	if err := os.Mkdir(secrets, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(secrets, "keys"), []byte("a,b"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(secrets, "user"), []byte("ignored"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var c credentials
+	report, err := configx.Load(&c,
+		configx.WithFiles(config),
+		configx.WithSecretKey(key),
+		configx.WithSecretsDir(secrets),
+		configx.WithEnv("APP"),
+		env(map[string]string{"APP_PIN_FILE": pinFile}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.User != "admin" || c.Password.Value() != "from-file" || c.PIN.Value() != 4321 || fmt.Sprint(c.Keys.Value()) != "[a b]" {
+		t.Errorf("loaded %q %q %d %q", c.User, c.Password.Value(), c.PIN.Value(), c.Keys.Value())
+	}
+	if o := report.Origin("PIN"); o != (configx.Origin{Source: configx.SourceEnv, Name: "APP_PIN_FILE"}) {
+		t.Errorf("origin of PIN = %v", o)
+	}
+	if o := report.Origin("Keys"); o.Name != filepath.Join(secrets, "keys") {
+		t.Errorf("origin of Keys = %v", o)
+	}
+
+	_, err = configx.Load(&c, configx.WithFiles(config))
+	if !errors.Is(err, configx.ErrNoSecretKey) {
+		t.Errorf("no key: err = %v", err)
+	}
+	_, err = configx.Load(&c, configx.WithFiles(config), configx.WithSecretKey([]byte("wrong")))
+	if err == nil || !strings.Contains(err.Error(), "Password (file "+config+"): encrypted secret") {
+		t.Errorf("wrong key: err = %v", err)
+	}
+	_, err = configx.Load(&c, configx.WithEnv("APP"), env(map[string]string{"APP_PIN": "12x4", "APP_PASSWORD": "a", "APP_PASSWORD_FILE": pinFile}))
+	if err == nil || strings.Contains(err.Error(), "12x4") || !strings.Contains(err.Error(), "both APP_PASSWORD and APP_PASSWORD_FILE are set") {
+		t.Errorf("invalid values: err = %v", err)
+	}
+}
@@ -0,0 +1,391 @@
+package configx
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/unsafe-risk/utilx/configx/optionalx"
+)
+
+var ErrInvalidPollInterval = errors.New("invalid poll interval, must be greater than 0s")
+
+// WatchOptions controls how a Watcher notices changes.
+type WatchOptions struct {
+	// Debounce is how long the files must stay unchanged before they are
+	// reloaded, so that a burst of writes causes a single reload.
+	Debounce time.Duration
+	// Poll makes the watcher stat the files every PollInterval instead of
+	// relying on inotify. Polling is also the fallback where inotify is not
+	// available.
+	Poll         bool
+	PollInterval time.Duration
+	// OnError is called with the error of every reload that failed. The
+	// previous configuration stays in place.
+	OnError func(err error)
+}
+
+type WatchOption = optionalx.Option[WatchOptions]
+
+func defaultWatchOptions() WatchOptions {
+	return WatchOptions{
+		Debounce:     100 * time.Millisecond,
+		PollInterval: time.Second,
+	}
+}
+
+func WithDebounce(d time.Duration) WatchOption {
+	return optionalx.Func(func(o *WatchOptions) {
+		o.Debounce = d
+	})
+}
+
+// WithPolling watches the files by polling every interval.
+func WithPolling(interval time.Duration) WatchOption {
+	return func(o *WatchOptions) error {
+		if interval <= 0 {
+			return ErrInvalidPollInterval
+		}
+		o.Poll = true
+		o.PollInterval = interval
+		return nil
+	}
+}
+
+func WithErrorHandler(f func(err error)) WatchOption {
+	return optionalx.Func(func(o *WatchOptions) {
+		o.OnError = f
+	})
+}
+
+// Watcher holds the configuration loaded by a Loader and reloads it when the
+// config files change. The configuration is replaced as a whole, so readers
+// see either the old or the new one. A reload that fails, including on
+// validation, keeps the old configuration.
+//
+// Values returned by Current must not be modified.
+type Watcher[T any] struct {
+	loader  *Loader
+	options WatchOptions
+
+	current atomic.Pointer[T]
+	report  atomic.Pointer[Report]
+
+	// reloadMu serializes reloads, and with them the calls to subscribers.
+	reloadMu sync.Mutex
+	subsMu   sync.Mutex
+	subs     []*subscription[T]
+	nextID   int
+
+	notifier  notifier
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+type subscription[T any] struct {
+	id int
+	f  func(old, new *T)
+}
+
+// notifier signals possible changes of the watched files.
+type notifier interface {
+	Events() <-chan struct{}
+	Close() error
+}
+
+// NewWatcher loads the configuration and starts watching the files of
+// loader. It fails if the first load fails.
+func NewWatcher[T any](loader *Loader, opts ...WatchOption) (*Watcher[T], error) {
+	options, err := optionalx.New(defaultWatchOptions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher[T]{
+		loader:  loader,
+		options: options,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	cfg, report, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(cfg)
+	w.report.Store(report)
+
+	files := loader.options.Files
+	if !options.Poll {
+		w.notifier, err = newInotify(files)
+	}
+	if options.Poll || err != nil {
+		w.notifier = newPoller(files, options.PollInterval)
+	}
+	go w.run()
+	return w, nil
+}
+
+// Current returns the configuration in effect.
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Report returns the report of the load of the current configuration.
+func (w *Watcher[T]) Report() *Report {
+	return w.report.Load()
+}
+
+// Reload loads the configuration now. If it is valid and differs from the
+// current one, it replaces it and the subscribers are called before Reload
+// returns.
+func (w *Watcher[T]) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	cfg, report, err := w.load()
+	if err != nil {
+		return err
+	}
+	w.report.Store(report)
+	old := w.current.Load()
+	if reflect.DeepEqual(old, cfg) {
+		return nil
+	}
+	w.current.Store(cfg)
+
+	w.subsMu.Lock()
+	subs := append([]*subscription[T](nil), w.subs...)
+	w.subsMu.Unlock()
+	for _, s := range subs {
+		s.f(old, cfg)
+	}
+	return nil
+}
+
+func (w *Watcher[T]) load() (*T, *Report, error) {
+	cfg := new(T)
+	report, err := w.loader.Load(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, report, nil
+}
+
+// Subscribe calls f after every change of the configuration, with the old
+// and the new one. Calls are made one at a time, in the order of
+// subscription. The returned function cancels the subscription.
+func (w *Watcher[T]) Subscribe(f func(old, new *T)) (cancel func()) {
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+	w.nextID++
+	id := w.nextID
+	w.subs = append(w.subs, &subscription[T]{id: id, f: f})
+	return func() {
+		w.subsMu.Lock()
+		defer w.subsMu.Unlock()
+		for i, s := range w.subs {
+			if s.id == id {
+				w.subs = append(w.subs[:i:i], w.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Observe calls f with a nil old configuration and the current one, then
+// subscribes it like Subscribe. No reload can come between the first call
+// and the subscription, so f sees every configuration from the current one
+// on.
+func (w *Watcher[T]) Observe(f func(old, new *T)) (cancel func()) {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+	f(nil, w.current.Load())
+	return w.Subscribe(f)
+}
+
+// SubscribeField calls f when the field at path, such as "DB.Host", changes.
+// It panics if T has no such field or if the field is not of type F.
+func SubscribeField[T, F any](w *Watcher[T], path string, f func(old, new F)) (cancel func()) {
+	index := fieldIndex(reflect.TypeOf((*T)(nil)).Elem(), path, reflect.TypeOf((*F)(nil)).Elem())
+	return w.Subscribe(func(old, new *T) {
+		ov, nv := fieldByIndex(old, index), fieldByIndex(new, index)
+		if !reflect.DeepEqual(ov, nv) {
+			// a nil interface value does not assert to F
+			o, _ := ov.(F)
+			n, _ := nv.(F)
+			f(o, n)
+		}
+	})
+}
+
+func fieldIndex(t reflect.Type, path string, want reflect.Type) []int {
+	var index []int
+	ft := t
+	for _, name := range strings.Split(path, ".") {
+		st := indirect(ft)
+		if st.Kind() != reflect.Struct {
+			panic("configx: no field " + path + " in " + t.String())
+		}
+		sf, ok := st.FieldByName(name)
+		if !ok || !sf.IsExported() {
+			panic("configx: no field " + path + " in " + t.String())
+		}
+		index = append(index, sf.Index...)
+		ft = sf.Type
+	}
+	if ft != want {
+		panic("configx: field " + path + " of " + t.String() + " is a " + ft.String() + ", not a " + want.String())
+	}
+	return index
+}
+
+// fieldByIndex returns the field at index of *p, or its zero value if a nil
+// pointer is on the way.
+func fieldByIndex[T any](p *T, index []int) any {
+	v := reflect.ValueOf(p).Elem()
+	for _, i := range index {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Zero(fieldType(reflect.TypeOf(p).Elem(), index)).Interface()
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v.Interface()
+}
+
+func fieldType(t reflect.Type, index []int) reflect.Type {
+	for _, i := range index {
+		t = indirect(t).Field(i).Type
+	}
+	return t
+}
+
+// Close stops watching. The last configuration stays available.
+func (w *Watcher[T]) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		w.closeErr = w.notifier.Close()
+		<-w.done
+	})
+	return w.closeErr
+}
+
+func (w *Watcher[T]) run() {
+	defer close(w.done)
+	var timer *time.Timer
+	var fire <-chan time.Time
+	for {
+		select {
+		case <-w.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case _, ok := <-w.notifier.Events():
+			if !ok {
+				// the notifier failed; keep the configuration without watching
+				<-w.stop
+				return
+			}
+			if timer == nil {
+				timer = time.NewTimer(w.options.Debounce)
+			} else {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(w.options.Debounce)
+			}
+			fire = timer.C
+		case <-fire:
+			fire = nil
+			if err := w.Reload(); err != nil && w.options.OnError != nil {
+				w.options.OnError(err)
+			}
+		}
+	}
+}
+
+// poller signals a change when the size, modification time or existence of
+// a file changes.
+type poller struct {
+	events chan struct{}
+	stop   chan struct{}
+	once   sync.Once
+}
+
+type fileState struct {
+	exists bool
+	size   int64
+	mod    time.Time
+}
+
+func statFile(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: fi.Size(), mod: fi.ModTime()}
+}
+
+func newPoller(files []string, interval time.Duration) *poller {
+	p := &poller{
+		events: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+	states := make([]fileState, len(files))
+	for i, f := range files {
+		states[i] = statFile(f)
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			}
+			changed := false
+			for i, f := range files {
+				if s := statFile(f); s != states[i] {
+					states[i] = s
+					changed = true
+				}
+			}
+			if changed {
+				signal(p.events)
+			}
+		}
+	}()
+	return p
+}
+
+func (p *poller) Events() <-chan struct{} {
+	return p.events
+}
+
+func (p *poller) Close() error {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+	return nil
+}
+
+// signal sends on a channel with a buffer of one without blocking; a pending
+// signal already covers the new one.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,201 @@
+package configx_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/unsafe-risk/utilx/configx"
+)
+
+type watched struct {
+	Port    int `config:",required"`
+	Workers int `default:"1"`
+	DB      struct {
+		Host string
+	}
+}
+
+func (w *watched) Validate() error {
+	if w.Workers < 1 {
+		return errors.New("workers must be positive")
+	}
+	return nil
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	// write then rename, as editors and config management tools do
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newWatcher(t *testing.T, content string, opts ...configx.WatchOption) (*configx.Watcher[watched], string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfig(t, path, content)
+	loader, err := configx.NewLoader(configx.WithFiles(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := configx.NewWatcher[watched](loader, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w, path
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a reload")
+	}
+	panic("unreachable")
+}
+
+func TestWatcher(t *testing.T) {
+	for name, opts := range map[string][]configx.WatchOption{
+		"inotify": {configx.WithDebounce(10 * time.Millisecond)},
+		"poll":    {configx.WithDebounce(10 * time.Millisecond), configx.WithPolling(10 * time.Millisecond)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			errs := make(chan error, 10)
+			w, path := newWatcher(t, `{"port": 80, "db": {"host": "a"}}`, append(opts, configx.WithErrorHandler(func(err error) {
+				errs <- err
+			}))...)
+			if c := w.Current(); c.Port != 80 || c.Workers != 1 || c.DB.Host != "a" {
+				t.Fatalf("initial config %+v", c)
+			}
+
+			type change struct{ old, new *watched }
+			changes := make(chan change, 10)
+			w.Subscribe(func(old, new *watched) {
+				changes <- change{old, new}
+			})
+			hosts := make(chan [2]string, 10)
+			configx.SubscribeField(w, "DB.Host", func(old, new string) {
+				hosts <- [2]string{old, new}
+			})
+
+			writeConfig(t, path, `{"port": 8080, "db": {"host": "a"}}`)
+			c := receive(t, changes)
+			if c.old.Port != 80 || c.new.Port != 8080 || w.Current() != c.new {
+				t.Errorf("change %+v to %+v, current %+v", c.old, c.new, w.Current())
+			}
+
+			writeConfig(t, path, `{"port": 8080, "workers": 0, "db": {"host": "b"}}`)
+			if err := receive(t, errs); err.Error() != "configx: workers must be positive" {
+				t.Errorf("reload error %v", err)
+			}
+			if w.Current().DB.Host != "a" {
+				t.Errorf("invalid config replaced the current one: %+v", w.Current())
+			}
+
+			writeConfig(t, path, `{"port": 8080, "workers": 2, "db": {"host": "b"}}`)
+			if h := receive(t, hosts); h != [2]string{"a", "b"} {
+				t.Errorf("host change %q", h)
+			}
+			if c := receive(t, changes); c.new.Workers != 2 {
+				t.Errorf("change to %+v", c.new)
+			}
+			if len(hosts) != 0 {
+				t.Errorf("field subscriber called without a change of the field")
+			}
+		})
+	}
+}
+
+func TestWatcherObserve(t *testing.T) {
+	w, path := newWatcher(t, `{"port": 80}`, configx.WithDebounce(10*time.Millisecond))
+	type change struct{ old, new *watched }
+	changes := make(chan change, 10)
+	cancel := w.Observe(func(old, new *watched) {
+		changes <- change{old, new}
+	})
+	defer cancel()
+	if c := receive(t, changes); c.old != nil || c.new != w.Current() || c.new.Port != 80 {
+		t.Fatalf("first call (%+v, %+v), want (nil, current)", c.old, c.new)
+	}
+	writeConfig(t, path, `{"port": 81}`)
+	if c := receive(t, changes); c.old.Port != 80 || c.new.Port != 81 {
+		t.Errorf("change (%+v, %+v)", c.old, c.new)
+	}
+}
+
+func TestWatcherDebounce(t *testing.T) {
+	w, path := newWatcher(t, `{"port": 1}`, configx.WithDebounce(200*time.Millisecond))
+	var calls int32
+	done := make(chan int, 10)
+	cancel := w.Subscribe(func(old, new *watched) {
+		atomic.AddInt32(&calls, 1)
+		done <- new.Port
+	})
+
+	for port := 2; port <= 5; port++ {
+		writeConfig(t, path, `{"port": `+strconv.Itoa(port)+`}`)
+	}
+	if port := receive(t, done); port != 5 {
+		t.Errorf("reloaded port %d, want 5", port)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("%d reloads, want 1", n)
+	}
+
+	cancel()
+	writeConfig(t, path, `{"port": 6}`)
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 || w.Current().Port != 6 {
+		t.Errorf("cancelled subscriber called, or port %d", w.Current().Port)
+	}
+}
+
+func TestWatcherErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "workers: 2\n")
+	loader, err := configx.NewLoader(configx.WithFiles(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := configx.NewWatcher[watched](loader); !errors.Is(err, configx.ErrMissing) {
+		t.Errorf("initial load: err = %v", err)
+	}
+	if _, err := configx.NewWatcher[watched](loader, configx.WithPolling(0)); !errors.Is(err, configx.ErrInvalidPollInterval) {
+		t.Errorf("invalid option: err = %v", err)
+	}
+
+	writeConfig(t, path, "port: 1\n")
+	w, err := configx.NewWatcher[watched](loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	for _, path := range []string{"DB.Port", "Port.X", "DB"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("SubscribeField(%q) did not panic", path)
+				}
+			}()
+			configx.SubscribeField(w, path, func(old, new string) {})
+		}()
+	}
+	if w.Report().Origin("Port").Source != configx.SourceFile {
+		t.Errorf("report %v", w.Report())
+	}
+}
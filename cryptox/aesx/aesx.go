@@ -1 +1,6 @@
+// Package aesx encrypts and decrypts with AES.
+//
+// Seal and Open wrap ciphertexts in a versioned envelope that records the
+// algorithm, the key ID and how the key was derived, so keys and algorithms
+// can change without breaking existing ciphertexts.
 package aesx
@@ -7,30 +7,55 @@ import (
 	"crypto/sha256"
 )
 
+// EncryptCBC encrypts data with AES-256-CBC and PKCS#7 padding, and
+// authenticates the IV and the ciphertext with an HMAC-SHA256 tag. The
+// result is the IV, the ciphertext and the tag.
 func EncryptCBC(data []byte, key []byte) ([]byte, error) {
-	k := sha256.Sum256(key)
-	block, err := aes.NewCipher(k[:])
+	k, err := etmKey(key, "cbc")
 	if err != nil {
 		return nil, err
 	}
-	iv := make([]byte, block.BlockSize())
+	iv := make([]byte, ivSize)
 	if _, err := rand.Read(iv); err != nil {
 		return nil, err
 	}
-	mode := cipher.NewCBCEncrypter(block, iv)
-	result := make([]byte, len(data))
-	mode.CryptBlocks(result, data)
-	return append(iv, result...), nil
+	ciphertext, err := sealCBC(k, iv, data, iv)
+	if err != nil {
+		return nil, err
+	}
+	return append(iv, ciphertext...), nil
 }
 
+// DecryptCBC decrypts the output of EncryptCBC. It returns ErrTruncated or
+// ErrAuthentication if data was cut short or modified.
 func DecryptCBC(data []byte, key []byte) ([]byte, error) {
+	if len(data) < ivSize {
+		return nil, ErrTruncated
+	}
+	k, err := etmKey(key, "cbc")
+	if err != nil {
+		return nil, err
+	}
+	return openCBC(k, data[:ivSize], data[ivSize:], data[:ivSize])
+}
+
+// DecryptLegacyCBC decrypts the output of EncryptCBC before it padded and
+// authenticated, for Open with WithLegacy.
+//
+// Deprecated: the ciphertext is not authenticated; re-encrypt it with Seal.
+func DecryptLegacyCBC(data []byte, key []byte) ([]byte, error) {
 	k := sha256.Sum256(key)
 	block, err := aes.NewCipher(k[:])
 	if err != nil {
 		return nil, err
 	}
-	ivSize := block.BlockSize()
+	if len(data) < ivSize {
+		return nil, ErrTruncated
+	}
 	iv, ciphertext := data[:ivSize], data[ivSize:]
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrInvalidPadding
+	}
 	mode := cipher.NewCBCDecrypter(block, iv)
 	result := make([]byte, len(ciphertext))
 	mode.CryptBlocks(result, ciphertext)
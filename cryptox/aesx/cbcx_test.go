@@ -0,0 +1,102 @@
+package aesx_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/cryptox/aesx"
+)
+
+var modes = []struct {
+	name    string
+	encrypt func(data, key []byte) ([]byte, error)
+	decrypt func(data, key []byte) ([]byte, error)
+}{
+	{"cbc", aesx.EncryptCBC, aesx.DecryptCBC},
+	{"ctr", aesx.EncryptCTR, aesx.DecryptCTR},
+}
+
+func TestModesRoundTrip(t *testing.T) {
+	key := []byte("key")
+	for _, m := range modes {
+		for n := 0; n <= 40; n++ {
+			data := bytes.Repeat([]byte{'x'}, n)
+			blob, err := m.encrypt(data, key)
+			if err != nil {
+				t.Fatalf("%s: encrypt %d bytes: %v", m.name, n, err)
+			}
+			got, err := m.decrypt(blob, key)
+			if err != nil {
+				t.Fatalf("%s: decrypt %d bytes: %v", m.name, n, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("%s: decrypt = %q, want %q", m.name, got, data)
+			}
+		}
+	}
+}
+
+func TestModesTampered(t *testing.T) {
+	key := []byte("key")
+	for _, m := range modes {
+		blob, err := m.encrypt([]byte("attack at dawn"), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range blob {
+			tampered := append([]byte(nil), blob...)
+			tampered[i] ^= 0x80
+			if _, err := m.decrypt(tampered, key); !errors.Is(err, aesx.ErrAuthentication) {
+				t.Errorf("%s: tampered byte %d: err = %v", m.name, i, err)
+			}
+		}
+		if _, err := m.decrypt(blob, []byte("other key")); !errors.Is(err, aesx.ErrAuthentication) {
+			t.Errorf("%s: wrong key: err = %v", m.name, err)
+		}
+		for _, n := range []int{0, 15, 16, 47} {
+			if _, err := m.decrypt(blob[:n], key); !errors.Is(err, aesx.ErrTruncated) {
+				t.Errorf("%s: %d bytes: err = %v", m.name, n, err)
+			}
+		}
+	}
+	cbc, _ := aesx.EncryptCBC(nil, key)
+	ctr, _ := aesx.EncryptCTR(nil, key)
+	if _, err := aesx.DecryptCTR(cbc, key); !errors.Is(err, aesx.ErrAuthentication) {
+		t.Errorf("CBC blob with DecryptCTR: err = %v", err)
+	}
+	if _, err := aesx.DecryptCBC(append(ctr, make([]byte, 16)...), key); !errors.Is(err, aesx.ErrAuthentication) {
+		t.Errorf("CTR blob with DecryptCBC: err = %v", err)
+	}
+}
+
+func TestDecryptLegacy(t *testing.T) {
+	key := []byte("key")
+	k := sha256.Sum256(key)
+	block, _ := aes.NewCipher(k[:])
+	iv := bytes.Repeat([]byte{7}, 16)
+	plaintext := []byte("exactly 16 bytes")
+
+	cbc := make([]byte, 16)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(cbc, plaintext)
+	got, err := aesx.DecryptLegacyCBC(append(iv, cbc...), key)
+	if err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("DecryptLegacyCBC = %q, %v", got, err)
+	}
+	if _, err := aesx.DecryptLegacyCBC(iv[:8], key); !errors.Is(err, aesx.ErrTruncated) {
+		t.Errorf("DecryptLegacyCBC of 8 bytes: err = %v", err)
+	}
+	if _, err := aesx.DecryptLegacyCBC(append(iv, 1, 2, 3), key); !errors.Is(err, aesx.ErrInvalidPadding) {
+		t.Errorf("DecryptLegacyCBC of a partial block: err = %v", err)
+	}
+
+	ctr := make([]byte, 5)
+	cipher.NewCTR(block, iv).XORKeyStream(ctr, []byte("hello"))
+	got, err = aesx.Open(append(iv, ctr...), aesx.Keyring{"": key}, aesx.WithLegacy(aesx.DecryptLegacyCTR, ""))
+	if err != nil || string(got) != "hello" {
+		t.Errorf("Open with DecryptLegacyCTR = %q, %v", got, err)
+	}
+}
@@ -7,29 +7,51 @@ import (
 	"crypto/sha256"
 )
 
+// EncryptCTR encrypts data with AES-256-CTR and authenticates the IV and the
+// ciphertext with an HMAC-SHA256 tag. The result is the IV, the ciphertext
+// and the tag.
 func EncryptCTR(data []byte, key []byte) ([]byte, error) {
-	k := sha256.Sum256(key)
-	block, err := aes.NewCipher(k[:])
+	k, err := etmKey(key, "ctr")
 	if err != nil {
 		return nil, err
 	}
-	iv := make([]byte, block.BlockSize())
+	iv := make([]byte, ivSize)
 	if _, err := rand.Read(iv); err != nil {
 		return nil, err
 	}
-	mode := cipher.NewCTR(block, iv)
-	result := make([]byte, len(data))
-	mode.XORKeyStream(result, data)
-	return append(iv, result...), nil
+	ciphertext, err := sealCTR(k, iv, data, iv)
+	if err != nil {
+		return nil, err
+	}
+	return append(iv, ciphertext...), nil
 }
 
+// DecryptCTR decrypts the output of EncryptCTR. It returns ErrTruncated or
+// ErrAuthentication if data was cut short or modified.
 func DecryptCTR(data []byte, key []byte) ([]byte, error) {
+	if len(data) < ivSize {
+		return nil, ErrTruncated
+	}
+	k, err := etmKey(key, "ctr")
+	if err != nil {
+		return nil, err
+	}
+	return openCTR(k, data[:ivSize], data[ivSize:], data[:ivSize])
+}
+
+// DecryptLegacyCTR decrypts the output of EncryptCTR before it
+// authenticated, for Open with WithLegacy.
+//
+// Deprecated: the ciphertext is not authenticated; re-encrypt it with Seal.
+func DecryptLegacyCTR(data []byte, key []byte) ([]byte, error) {
 	k := sha256.Sum256(key)
 	block, err := aes.NewCipher(k[:])
 	if err != nil {
 		return nil, err
 	}
-	ivSize := block.BlockSize()
+	if len(data) < ivSize {
+		return nil, ErrTruncated
+	}
 	iv, ciphertext := data[:ivSize], data[ivSize:]
 	mode := cipher.NewCTR(block, iv)
 	result := make([]byte, len(ciphertext))
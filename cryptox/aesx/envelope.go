@@ -0,0 +1,369 @@
+package aesx
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/unsafe-risk/utilx/configx/optionalx"
+)
+
+var (
+	ErrTruncated        = errors.New("ciphertext too short")
+	ErrAuthentication   = errors.New("message authentication failed")
+	ErrInvalidEnvelope  = errors.New("invalid envelope")
+	ErrUnknownKeyID     = errors.New("unknown key id")
+	ErrUnknownAlgorithm = errors.New("unknown algorithm")
+	ErrUnknownKDF       = errors.New("unknown key derivation function")
+	ErrArgon2Limit      = errors.New("argon2 parameters exceed the limit")
+	ErrInvalidArgon2    = errors.New("invalid argon2 parameters")
+	ErrInvalidKeyID     = errors.New("invalid key id, must be at most 255 bytes")
+)
+
+// Algorithm identifies the cipher of an envelope.
+type Algorithm uint8
+
+const (
+	// AlgorithmGCM is AES-256-GCM with a 12 byte nonce.
+	AlgorithmGCM Algorithm = 1
+	// AlgorithmCBCHMAC is AES-256-CBC with PKCS#7 padding and an
+	// HMAC-SHA256 tag, with a 16 byte IV.
+	AlgorithmCBCHMAC Algorithm = 2
+	// AlgorithmCTRHMAC is AES-256-CTR with an HMAC-SHA256 tag, with a 16
+	// byte IV.
+	AlgorithmCTRHMAC Algorithm = 3
+)
+
+// KDF identifies how the AES key of an envelope is derived from the key.
+type KDF uint8
+
+const (
+	// KDFHKDF is HKDF-SHA256 with a random salt, for high-entropy keys.
+	KDFHKDF KDF = 1
+	// KDFArgon2id is Argon2id with a random salt, for passphrases.
+	KDFArgon2id KDF = 2
+)
+
+// Argon2Params are the costs of Argon2id. Memory is in KiB.
+type Argon2Params struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// DefaultArgon2Params are the second recommended option of RFC 9106.
+var DefaultArgon2Params = Argon2Params{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// The envelope is
+//
+//	magic [4] | version | algorithm | kdf | len(key id) | key id |
+//	kdf parameters | nonce | ciphertext
+//
+// where the parameters of HKDF are a 32 byte salt, and those of Argon2id the
+// time and memory as big endian uint32, the threads and a 16 byte salt.
+// Everything before the ciphertext is authenticated. Blobs of the legacy
+// functions start with a random IV, whose first five bytes match the magic
+// and version with a chance of 2^-40.
+const (
+	envelopeVersion = 1
+	hkdfSaltSize    = 32
+	argon2SaltSize  = 16
+)
+
+var envelopeMagic = [4]byte{0xa3, 0x5e, 0x4e, 0x56}
+
+type SealOptions struct {
+	Algorithm Algorithm
+	KDF       KDF
+	KeyID     string
+	Argon2    Argon2Params
+}
+
+type SealOption = optionalx.Option[SealOptions]
+
+func defaultSealOptions() SealOptions {
+	return SealOptions{
+		Algorithm: AlgorithmGCM,
+		KDF:       KDFHKDF,
+		Argon2:    DefaultArgon2Params,
+	}
+}
+
+// WithKeyID records id in the envelope, so Open can find the key in a
+// keyring and keys can be rotated.
+func WithKeyID(id string) SealOption {
+	return func(o *SealOptions) error {
+		if len(id) > 255 {
+			return ErrInvalidKeyID
+		}
+		o.KeyID = id
+		return nil
+	}
+}
+
+// WithPassphrase derives the AES key from a passphrase with Argon2id and
+// the given costs, instead of with HKDF.
+func WithPassphrase(params Argon2Params) SealOption {
+	return func(o *SealOptions) error {
+		if params.Time == 0 || params.Threads == 0 || params.Memory < 8*uint32(params.Threads) {
+			return ErrInvalidArgon2
+		}
+		o.KDF = KDFArgon2id
+		o.Argon2 = params
+		return nil
+	}
+}
+
+func WithAlgorithm(alg Algorithm) SealOption {
+	return func(o *SealOptions) error {
+		if _, ok := algorithms[alg]; !ok {
+			return ErrUnknownAlgorithm
+		}
+		o.Algorithm = alg
+		return nil
+	}
+}
+
+// algorithm seals and opens with a key of keySize bytes.
+type algorithm struct {
+	keySize, nonceSize int
+	seal               func(key, nonce, plaintext, header []byte) ([]byte, error)
+	open               func(key, nonce, ciphertext, header []byte) ([]byte, error)
+}
+
+var algorithms = map[Algorithm]algorithm{
+	AlgorithmGCM:     {keySize: 32, nonceSize: 12, seal: sealGCM, open: openGCM},
+	AlgorithmCBCHMAC: {keySize: etmKeySize, nonceSize: ivSize, seal: sealCBC, open: openCBC},
+	AlgorithmCTRHMAC: {keySize: etmKeySize, nonceSize: ivSize, seal: sealCTR, open: openCTR},
+}
+
+// Seal encrypts plaintext under key into a self-describing envelope. The AES
+// key is derived from key with HKDF-SHA256 and a random salt, unless
+// WithPassphrase is given.
+func Seal(plaintext, key []byte, opts ...SealOption) ([]byte, error) {
+	o, err := optionalx.New(defaultSealOptions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	alg := algorithms[o.Algorithm]
+
+	header := make([]byte, 0, 64)
+	header = append(header, envelopeMagic[:]...)
+	header = append(header, envelopeVersion, byte(o.Algorithm), byte(o.KDF), byte(len(o.KeyID)))
+	header = append(header, o.KeyID...)
+	var salt []byte
+	switch o.KDF {
+	case KDFHKDF:
+		salt = make([]byte, hkdfSaltSize)
+	case KDFArgon2id:
+		header = binary.BigEndian.AppendUint32(header, o.Argon2.Time)
+		header = binary.BigEndian.AppendUint32(header, o.Argon2.Memory)
+		header = append(header, o.Argon2.Threads)
+		salt = make([]byte, argon2SaltSize)
+	default:
+		return nil, ErrUnknownKDF
+	}
+	nonce := make([]byte, alg.nonceSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header = append(header, salt...)
+	header = append(header, nonce...)
+
+	k, err := deriveKey(o.KDF, key, salt, o.Argon2, header[:len(envelopeMagic)+3], alg.keySize)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := alg.seal(k, nonce, plaintext, header)
+	if err != nil {
+		return nil, err
+	}
+	return append(header, ciphertext...), nil
+}
+
+// Keyring maps key IDs to keys.
+type Keyring map[string][]byte
+
+type OpenOptions struct {
+	// Legacy decrypts blobs that are not envelopes, with the key of
+	// LegacyKeyID. Nil rejects them.
+	Legacy      func(data, key []byte) ([]byte, error)
+	LegacyKeyID string
+	// MaxArgon2 bounds the costs an envelope may ask for, since they are
+	// chosen by whoever made it.
+	MaxArgon2 Argon2Params
+}
+
+type OpenOption = optionalx.Option[OpenOptions]
+
+func defaultOpenOptions() OpenOptions {
+	return OpenOptions{
+		Legacy:    DecryptGCM,
+		MaxArgon2: Argon2Params{Time: 16, Memory: 1 << 20, Threads: 16},
+	}
+}
+
+// WithLegacy decrypts blobs without an envelope with decrypt, such as
+// DecryptGCM, DecryptLegacyCBC or DecryptLegacyCTR, and the key of keyID. A
+// nil decrypt rejects them. By default, they are taken for blobs of
+// EncryptGCM under the key of the empty ID.
+func WithLegacy(decrypt func(data, key []byte) ([]byte, error), keyID string) OpenOption {
+	return optionalx.Func(func(o *OpenOptions) {
+		o.Legacy = decrypt
+		o.LegacyKeyID = keyID
+	})
+}
+
+func WithMaxArgon2(params Argon2Params) OpenOption {
+	return optionalx.Func(func(o *OpenOptions) {
+		o.MaxArgon2 = params
+	})
+}
+
+// Open decrypts an envelope made by Seal with the key of its key ID in keys.
+// Blobs that are not envelopes are handed to the legacy decryption, which
+// reads the output of EncryptGCM by default.
+func Open(blob []byte, keys Keyring, opts ...OpenOption) ([]byte, error) {
+	o, err := optionalx.New(defaultOpenOptions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(blob, keys, o)
+	if err != errNotEnvelope {
+		return plaintext, err
+	}
+	if o.Legacy == nil {
+		return nil, ErrInvalidEnvelope
+	}
+	key, ok := keys[o.LegacyKeyID]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return o.Legacy(blob, key)
+}
+
+var errNotEnvelope = errors.New("not an envelope")
+
+func open(blob []byte, keys Keyring, o OpenOptions) ([]byte, error) {
+	r := envelopeReader{b: blob}
+	if !bytes.Equal(r.next(len(envelopeMagic)), envelopeMagic[:]) || r.byte() != envelopeVersion {
+		return nil, errNotEnvelope
+	}
+	alg, ok := algorithms[Algorithm(r.byte())]
+	if !ok {
+		return nil, ErrUnknownAlgorithm
+	}
+	kdf := KDF(r.byte())
+	id := string(r.next(int(r.byte())))
+
+	var params Argon2Params
+	var salt []byte
+	switch kdf {
+	case KDFHKDF:
+		salt = r.next(hkdfSaltSize)
+	case KDFArgon2id:
+		params.Time = binary.BigEndian.Uint32(r.next(4))
+		params.Memory = binary.BigEndian.Uint32(r.next(4))
+		params.Threads = r.byte()
+		salt = r.next(argon2SaltSize)
+	default:
+		return nil, ErrUnknownKDF
+	}
+	nonce := r.next(alg.nonceSize)
+	if r.short {
+		return nil, ErrInvalidEnvelope
+	}
+	header, ciphertext := blob[:r.off], blob[r.off:]
+
+	key, ok := keys[id]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if kdf == KDFArgon2id && (params.Time > o.MaxArgon2.Time || params.Memory > o.MaxArgon2.Memory ||
+		params.Threads > o.MaxArgon2.Threads || params.Threads == 0 || params.Time == 0) {
+		return nil, ErrArgon2Limit
+	}
+	k, err := deriveKey(kdf, key, salt, params, header[:len(envelopeMagic)+3], alg.keySize)
+	if err != nil {
+		return nil, err
+	}
+	return alg.open(k, nonce, ciphertext, header)
+}
+
+// deriveKey derives n bytes of key material. The info of HKDF binds the key
+// to the version, algorithm and KDF of the envelope.
+func deriveKey(kdf KDF, key, salt []byte, params Argon2Params, info []byte, n int) ([]byte, error) {
+	switch kdf {
+	case KDFHKDF:
+		k := make([]byte, n)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, info), k); err != nil {
+			return nil, err
+		}
+		return k, nil
+	case KDFArgon2id:
+		return argon2.IDKey(key, salt, params.Time, params.Memory, params.Threads, uint32(n)), nil
+	}
+	return nil, ErrUnknownKDF
+}
+
+type envelopeReader struct {
+	b     []byte
+	off   int
+	short bool
+}
+
+func (r *envelopeReader) next(n int) []byte {
+	if r.short || len(r.b)-r.off < n {
+		r.short = true
+		return make([]byte, n)
+	}
+	p := r.b[r.off : r.off+n]
+	r.off += n
+	return p
+}
+
+func (r *envelopeReader) byte() byte {
+	return r.next(1)[0]
+}
+
+func sealGCM(key, nonce, plaintext, header []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, nonce, plaintext, header), nil
+}
+
+func openGCM(key, nonce, ciphertext, header []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.Overhead() {
+		return nil, ErrTruncated
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return nil, ErrAuthentication
+	}
+	return plaintext, nil
+}
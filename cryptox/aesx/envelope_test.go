@@ -0,0 +1,139 @@
+package aesx_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/cryptox/aesx"
+)
+
+var testArgon2 = aesx.Argon2Params{Time: 1, Memory: 64, Threads: 1}
+
+func TestSealOpen(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	plaintext := []byte("hello, envelope")
+
+	tests := []struct {
+		name string
+		opts []aesx.SealOption
+		keys aesx.Keyring
+	}{
+		{"hkdf", nil, aesx.Keyring{"": key}},
+		{"key id", []aesx.SealOption{aesx.WithKeyID("2024")}, aesx.Keyring{"2023": []byte("old"), "2024": key}},
+		{"argon2id", []aesx.SealOption{aesx.WithPassphrase(testArgon2)}, aesx.Keyring{"": key}},
+		{"cbc", []aesx.SealOption{aesx.WithAlgorithm(aesx.AlgorithmCBCHMAC)}, aesx.Keyring{"": key}},
+		{"ctr", []aesx.SealOption{aesx.WithAlgorithm(aesx.AlgorithmCTRHMAC), aesx.WithKeyID("k")}, aesx.Keyring{"k": key}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blob, err := aesx.Seal(plaintext, key, tt.opts...)
+			if err != nil {
+				t.Fatalf("Seal: %v", err)
+			}
+			got, err := aesx.Open(blob, tt.keys)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Errorf("Open = %q, want %q", got, plaintext)
+			}
+		})
+	}
+}
+
+func TestSealRandomized(t *testing.T) {
+	key := []byte("key")
+	a, _ := aesx.Seal([]byte("same"), key)
+	b, _ := aesx.Seal([]byte("same"), key)
+	if bytes.Equal(a, b) {
+		t.Error("two envelopes of the same plaintext are equal")
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	key := []byte("key")
+	blob, err := aesx.Seal([]byte("secret"), key, aesx.WithKeyID("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := aesx.Open(blob, aesx.Keyring{"b": key}); !errors.Is(err, aesx.ErrUnknownKeyID) {
+		t.Errorf("unknown key id: err = %v", err)
+	}
+	if _, err := aesx.Open(blob, aesx.Keyring{"a": []byte("other")}); !errors.Is(err, aesx.ErrAuthentication) {
+		t.Errorf("wrong key: err = %v", err)
+	}
+	for i := range blob {
+		tampered := append([]byte(nil), blob...)
+		tampered[i] ^= 1
+		if _, err := aesx.Open(tampered, aesx.Keyring{"a": key}); err == nil {
+			t.Errorf("tampered byte %d: no error", i)
+		}
+	}
+	if _, err := aesx.Open(blob[:len(blob)-17], aesx.Keyring{"a": key}); !errors.Is(err, aesx.ErrTruncated) {
+		t.Errorf("truncated ciphertext: err = %v", err)
+	}
+	if _, err := aesx.Open(blob[:20], aesx.Keyring{"a": key}); !errors.Is(err, aesx.ErrInvalidEnvelope) {
+		t.Errorf("truncated header: err = %v", err)
+	}
+	if _, err := aesx.Open(nil, aesx.Keyring{"": key}); !errors.Is(err, aesx.ErrTruncated) {
+		t.Errorf("empty: err = %v", err)
+	}
+}
+
+func TestOpenArgon2Limit(t *testing.T) {
+	key := []byte("passphrase")
+	blob, err := aesx.Seal([]byte("secret"), key, aesx.WithPassphrase(aesx.Argon2Params{Time: 2, Memory: 64, Threads: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = aesx.Open(blob, aesx.Keyring{"": key}, aesx.WithMaxArgon2(aesx.Argon2Params{Time: 1, Memory: 1 << 20, Threads: 4}))
+	if !errors.Is(err, aesx.ErrArgon2Limit) {
+		t.Errorf("err = %v, want ErrArgon2Limit", err)
+	}
+}
+
+func TestOpenLegacy(t *testing.T) {
+	key := []byte("legacy key")
+	plaintext := []byte("sixteen byte msg")
+
+	gcm, err := aesx.EncryptGCM(plaintext, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := aesx.Open(gcm, aesx.Keyring{"": key})
+	if err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("legacy GCM: Open = %q, %v", got, err)
+	}
+	if _, err := aesx.Open(gcm, aesx.Keyring{"": key}, aesx.WithLegacy(nil, "")); !errors.Is(err, aesx.ErrInvalidEnvelope) {
+		t.Errorf("legacy disabled: err = %v", err)
+	}
+
+	// EncryptCBC before it padded and authenticated
+	k := sha256.Sum256(key)
+	block, _ := aes.NewCipher(k[:])
+	cbc := make([]byte, 16+len(plaintext))
+	copy(cbc, "sixteen byte iv!")
+	cipher.NewCBCEncrypter(block, cbc[:16]).CryptBlocks(cbc[16:], plaintext)
+	got, err = aesx.Open(cbc, aesx.Keyring{"old": key}, aesx.WithLegacy(aesx.DecryptLegacyCBC, "old"))
+	if err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("legacy CBC: Open = %q, %v", got, err)
+	}
+}
+
+func TestSealOptionErrors(t *testing.T) {
+	key := []byte("key")
+	if _, err := aesx.Seal(nil, key, aesx.WithKeyID(string(make([]byte, 256)))); !errors.Is(err, aesx.ErrInvalidKeyID) {
+		t.Errorf("long key id: err = %v", err)
+	}
+	if _, err := aesx.Seal(nil, key, aesx.WithPassphrase(aesx.Argon2Params{})); !errors.Is(err, aesx.ErrInvalidArgon2) {
+		t.Errorf("zero argon2 params: err = %v", err)
+	}
+	if _, err := aesx.Seal(nil, key, aesx.WithAlgorithm(0)); !errors.Is(err, aesx.ErrUnknownAlgorithm) {
+		t.Errorf("unknown algorithm: err = %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package aesx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/unsafe-risk/utilx/cryptox/hmacx"
+)
+
+var ErrInvalidPadding = errors.New("invalid padding")
+
+// The keys of the encrypt-then-MAC modes are an AES-256 key followed by an
+// HMAC-SHA256 key.
+const (
+	etmKeySize = 32 + 32
+	ivSize     = aes.BlockSize
+)
+
+// etmKey derives the keys of mode from key with HKDF, so the same key used
+// with different modes gives unrelated keys.
+func etmKey(key []byte, mode string) ([]byte, error) {
+	return deriveKey(KDFHKDF, key, nil, Argon2Params{}, []byte("aesx "+mode), etmKeySize)
+}
+
+// sealCBC encrypts plaintext with AES-CBC and PKCS#7 padding, and sealCTR
+// with AES-CTR. Both append an HMAC-SHA256 tag of header, which ends with
+// the IV, and the ciphertext.
+func sealCBC(key, iv, plaintext, header []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := pad(plaintext)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+	return appendTag(ciphertext, header, key[32:]), nil
+}
+
+func openCBC(key, iv, ciphertext, header []byte) ([]byte, error) {
+	if len(ciphertext) < aes.BlockSize+hmacx.Size {
+		return nil, ErrTruncated
+	}
+	ciphertext, err := verifyTag(ciphertext, header, key[32:])
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrInvalidPadding
+	}
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	return unpad(plaintext)
+}
+
+func sealCTR(key, iv, plaintext, header []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, len(plaintext), len(plaintext)+hmacx.Size)
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, plaintext)
+	return appendTag(ciphertext, header, key[32:]), nil
+}
+
+func openCTR(key, iv, ciphertext, header []byte) ([]byte, error) {
+	if len(ciphertext) < hmacx.Size {
+		return nil, ErrTruncated
+	}
+	ciphertext, err := verifyTag(ciphertext, header, key[32:])
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, iv).XORKeyStream(plaintext, ciphertext)
+	return plaintext, nil
+}
+
+func appendTag(ciphertext, header, key []byte) []byte {
+	h := hmacx.New(key)
+	h.Write(header)
+	h.Write(ciphertext)
+	return h.Sum(ciphertext)
+}
+
+// verifyTag checks the tag at the end of data and returns the ciphertext
+// before it.
+func verifyTag(data, header, key []byte) ([]byte, error) {
+	ciphertext, tag := data[:len(data)-hmacx.Size], data[len(data)-hmacx.Size:]
+	h := hmacx.New(key)
+	h.Write(header)
+	h.Write(ciphertext)
+	if !hmacx.Equal(h.Sum(nil), tag) {
+		return nil, ErrAuthentication
+	}
+	return ciphertext, nil
+}
+
+// pad returns a copy of b padded with PKCS#7, with room for a tag.
+func pad(b []byte) []byte {
+	n := aes.BlockSize - len(b)%aes.BlockSize
+	padded := make([]byte, len(b)+n, len(b)+n+hmacx.Size)
+	copy(padded, b)
+	for i := len(b); i < len(padded); i++ {
+		padded[i] = byte(n)
+	}
+	return padded
+}
+
+// unpad removes PKCS#7 padding from b, which is a non-empty multiple of the
+// block size. The time it takes does not depend on the padding.
+func unpad(b []byte) ([]byte, error) {
+	n := b[len(b)-1]
+	good := subtle.ConstantTimeLessOrEq(1, int(n)) & subtle.ConstantTimeLessOrEq(int(n), aes.BlockSize)
+	for i := 1; i <= aes.BlockSize; i++ {
+		inPadding := subtle.ConstantTimeLessOrEq(i, int(n))
+		equal := subtle.ConstantTimeByteEq(b[len(b)-i], n)
+		good &= (1 - inPadding) | equal
+	}
+	if good != 1 {
+		return nil, ErrInvalidPadding
+	}
+	return b[:len(b)-int(n)], nil
+}
@@ -1,3 +1,4 @@
+// Package bcryptx hashes passwords with bcrypt.
 package bcryptx
 
 import (
@@ -1 +1,2 @@
+// Package cryptox groups cryptographic helpers.
 package cryptox
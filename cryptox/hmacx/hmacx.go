@@ -1 +1,33 @@
+// Package hmacx computes and verifies HMAC-SHA256 tags.
 package hmacx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+)
+
+// Size is the length of a tag in bytes.
+const Size = sha256.Size
+
+// New returns an HMAC-SHA256 hash keyed with key.
+func New(key []byte) hash.Hash {
+	return hmac.New(sha256.New, key)
+}
+
+// Sum returns the tag of data under key.
+func Sum(data, key []byte) []byte {
+	h := New(key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// Verify reports whether tag is the tag of data under key, in constant time.
+func Verify(data, tag, key []byte) bool {
+	return Equal(Sum(data, key), tag)
+}
+
+// Equal compares two tags in constant time.
+func Equal(a, b []byte) bool {
+	return hmac.Equal(a, b)
+}
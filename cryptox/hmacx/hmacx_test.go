@@ -0,0 +1,27 @@
+package hmacx_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/unsafe-risk/utilx/cryptox/hmacx"
+)
+
+// RFC 4231, test case 2
+func TestSum(t *testing.T) {
+	want, _ := hex.DecodeString("5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843")
+	got := hmacx.Sum([]byte("what do ya want for nothing?"), []byte("Jefe"))
+	if !bytes.Equal(got, want) {
+		t.Errorf("Sum = %x, want %x", got, want)
+	}
+	if !hmacx.Verify([]byte("what do ya want for nothing?"), want, []byte("Jefe")) {
+		t.Error("Verify rejected a valid tag")
+	}
+	if hmacx.Verify([]byte("what do ya want for nothing!"), want, []byte("Jefe")) {
+		t.Error("Verify accepted the tag of other data")
+	}
+	if hmacx.Verify([]byte("what do ya want for nothing?"), want[:16], []byte("Jefe")) {
+		t.Error("Verify accepted a truncated tag")
+	}
+}
@@ -1,3 +1,4 @@
+// Package passhashx hashes and verifies passwords with Argon2id.
 package passhashx
 
 import (
@@ -1 +1,2 @@
+// Package debugx groups debugging helpers.
 package debugx
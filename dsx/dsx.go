@@ -1,3 +1,4 @@
+// Package dsx defines the interfaces of the generic data structures below it.
 package dsx
 
 type Deque[T any] interface {
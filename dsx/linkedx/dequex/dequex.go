@@ -1,3 +1,4 @@
+// Package dequex implements a double-ended queue on a linked list.
 package dequex
 
 import (
@@ -89,11 +90,14 @@ func (t *Deque[T]) PopFront() (data T, ok bool) {
 	data = t.head.data[t.head.start]
 	t.head.start++
 	if t.head.start == t.head.end {
-		t.pool.Put(t.head)
-		t.head = t.head.next
+		n := t.head
+		t.head = n.next
 		if t.head != nil {
 			t.head.prev = nil
+		} else {
+			t.tail = nil
 		}
+		t.release(n)
 	}
 	return data, true
 }
@@ -105,15 +109,24 @@ func (t *Deque[T]) PopBack() (data T, ok bool) {
 	t.tail.end--
 	data = t.tail.data[t.tail.end]
 	if t.tail.start == t.tail.end {
-		t.pool.Put(t.tail)
-		t.tail = t.tail.prev
+		n := t.tail
+		t.tail = n.prev
 		if t.tail != nil {
 			t.tail.next = nil
+		} else {
+			t.head = nil
 		}
+		t.release(n)
 	}
 	return data, true
 }
 
+// release clears n so pooled nodes keep no stale links or values.
+func (t *Deque[T]) release(n *node[T]) {
+	*n = node[T]{}
+	t.pool.Put(n)
+}
+
 func (t *Deque[T]) PeekFront() (data T, ok bool) {
 	if t.head == nil {
 		return
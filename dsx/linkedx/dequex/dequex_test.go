@@ -30,3 +30,32 @@ func TestDeque(t *testing.T) {
 		}
 	}
 }
+
+func TestDequeReuse(t *testing.T) {
+	d := dequex.New[int]()
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 200; i++ {
+			d.PushBack(i)
+		}
+		for i := 0; i < 200; i++ {
+			if v, ok := d.PopFront(); !ok || v != i {
+				t.Fatalf("PopFront = %d, %v, want %d", v, ok, i)
+			}
+		}
+		if _, ok := d.PopBack(); ok {
+			t.Fatal("PopBack on empty deque succeeded")
+		}
+
+		for i := 0; i < 200; i++ {
+			d.PushFront(i)
+		}
+		for i := 0; i < 200; i++ {
+			if v, ok := d.PopFront(); !ok || v != 199-i {
+				t.Fatalf("PopFront = %d, %v, want %d", v, ok, 199-i)
+			}
+		}
+		if _, ok := d.PeekBack(); ok {
+			t.Fatal("PeekBack on empty deque succeeded")
+		}
+	}
+}
@@ -1 +1,2 @@
+// Package linkedx groups data structures built on linked nodes.
 package linkedx
@@ -1,3 +1,4 @@
+// Package listx implements a singly linked list.
 package listx
 
 import (
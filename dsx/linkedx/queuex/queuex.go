@@ -1,3 +1,4 @@
+// Package queuex implements a FIFO queue on a linked list.
 package queuex
 
 import (
@@ -46,9 +47,15 @@ func (q *Queue[T]) Dequeue() (data T, ok bool) {
 	if q.head == nil {
 		return
 	}
-	q.pool.Put(q.head)
-	q.head = q.head.next
-	return q.head.data, true
+	n := q.head
+	q.head = n.next
+	if q.head == nil {
+		q.tail = nil
+	}
+	data = n.data
+	*n = node[T]{}
+	q.pool.Put(n)
+	return data, true
 }
 
 func (q *Queue[T]) Peek() (data T, ok bool) {
@@ -0,0 +1,27 @@
+package queuex_test
+
+import (
+	"testing"
+
+	"github.com/unsafe-risk/utilx/dsx/linkedx/queuex"
+)
+
+func TestQueue(t *testing.T) {
+	q := queuex.New[int]()
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 100; i++ {
+			q.Enqueue(i)
+		}
+		for i := 0; i < 100; i++ {
+			if v, ok := q.Dequeue(); !ok || v != i {
+				t.Fatalf("Dequeue = %d, %v, want %d", v, ok, i)
+			}
+		}
+		if !q.IsEmpty() {
+			t.Fatal("queue is not empty")
+		}
+		if _, ok := q.Dequeue(); ok {
+			t.Fatal("Dequeue on empty queue succeeded")
+		}
+	}
+}
@@ -1,3 +1,4 @@
+// Package stackx implements a LIFO stack on a linked list.
 package stackx
 
 import (
@@ -1 +1,2 @@
+// Package mapx groups map implementations.
 package mapx
@@ -1,3 +1,4 @@
+// Package ordmapx implements a map that iterates its keys in order.
 package ordmapx
 
 import (
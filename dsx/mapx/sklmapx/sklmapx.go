@@ -1,8 +1,10 @@
+// Package sklmapx implements an ordered map on a skip list.
 package sklmapx
 
 import (
 	"fmt"
 
+	"github.com/unsafe-risk/utilx/algox/randx"
 	"golang.org/x/exp/constraints"
 )
 
@@ -11,7 +13,7 @@ const MAX_HEIGHT = 16
 func (l *skl[K, V]) rand_height() int {
 	var h int = 1
 	for ; h < MAX_HEIGHT; h++ {
-		if splitmix64(&l.seed)&0x1 == 1 {
+		if l.src.Uint64()&0x1 == 1 {
 			return h
 		}
 	}
@@ -21,7 +23,7 @@ func (l *skl[K, V]) rand_height() int {
 type skl[K constraints.Ordered, V any] struct {
 	head *sknode[K, V]
 	tail *sknode[K, V]
-	seed uint64
+	src  randx.Source
 	len  uint
 }
 
@@ -109,13 +111,23 @@ func (m *SkipListMap[K, V]) Get(key K) (V, bool) {
 }
 
 func New[K constraints.Ordered, V any]() *SkipListMap[K, V] {
+	return NewWithSource[K, V](randx.NewSplitMix64(randx.Seed()))
+}
+
+// NewWithSource returns a map that draws node heights from src, which makes
+// its layout reproducible for a seeded source. The map uses src without
+// locking, so src must not be shared with other goroutines. It panics if src
+// is nil.
+func NewWithSource[K constraints.Ordered, V any](src randx.Source) *SkipListMap[K, V] {
+	if src == nil {
+		panic("sklmapx: nil source")
+	}
 	head := &sknode[K, V]{}
 	tail := &sknode[K, V]{}
 	for i := 0; i < MAX_HEIGHT; i++ {
 		head.next[i] = tail
 	}
-	seed := root_next()
-	return &SkipListMap[K, V]{skl: skl[K, V]{head: head, tail: tail, seed: seed}}
+	return &SkipListMap[K, V]{skl: skl[K, V]{head: head, tail: tail, src: src}}
 }
 
 func (m *SkipListMap[K, V]) Iterator() *Iterator[K, V] {
@@ -187,7 +199,7 @@ func (m *SkipListMap[K, V]) ToMap() map[K]V {
 
 func (m *SkipListMap[K, V]) Clear() {
 	// Clear the map by creating a new one.
-	*m = *New[K, V]()
+	*m = *NewWithSource[K, V](m.skl.src)
 }
 
 type Iterator[K constraints.Ordered, V any] struct {
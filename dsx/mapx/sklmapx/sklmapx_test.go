@@ -227,3 +227,12 @@ func TestSkipListMap(t *testing.T) {
 		t.Errorf("expected %v, got %v", len(m), idx)
 	}
 }
+
+func TestNewWithNilSource(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "sklmapx: nil source" {
+			t.Errorf("recovered %v, want the nil source panic", r)
+		}
+	}()
+	sklmapx.NewWithSource[string, int](nil)
+}
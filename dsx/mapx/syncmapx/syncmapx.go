@@ -1,3 +1,4 @@
+// Package syncmapx implements a map guarded by a read-write mutex.
 package syncmapx
 
 import "sync"
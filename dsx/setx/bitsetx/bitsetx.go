@@ -1,3 +1,4 @@
+// Package bitsetx implements a set of small integers in an unsigned integer.
 package bitsetx
 
 import (
@@ -1 +1,2 @@
+// Package setx groups set implementations.
 package setx
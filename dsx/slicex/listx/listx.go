@@ -1,3 +1,4 @@
+// Package listx implements a list on a slice.
 package listx
 
 import "github.com/unsafe-risk/utilx/dsx"
@@ -1,3 +1,4 @@
+// Package queuex implements a bounded FIFO queue on a ring buffer.
 package queuex
 
 import (
@@ -1,8 +1,12 @@
+// Package slicex implements a slice type with chainable helpers, and groups
+// data structures built on slices.
 package slicex
 
 import (
 	"fmt"
 	"math/rand"
+
+	"github.com/unsafe-risk/utilx/algox/randx"
 )
 
 type Slice[T any] []T
@@ -142,6 +146,15 @@ func (s *Slice[T]) Shuffle() *Slice[T] {
 	return s
 }
 
+// ShuffleWith shuffles s in place with randomness drawn from src, which is
+// used, not copied, so it advances as if the shuffle had called it directly.
+func (s *Slice[T]) ShuffleWith(src randx.Source) *Slice[T] {
+	randx.Shuffle(src, len(*s), func(i, j int) {
+		(*s)[i], (*s)[j] = (*s)[j], (*s)[i]
+	})
+	return s
+}
+
 func (s *Slice[T]) Filter(f func(T) bool) *Slice[T] {
 	n := 0
 	for _, v := range *s {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/unsafe-risk/utilx/algox/randx"
 )
 
 func TestCopy(t *testing.T) {
@@ -404,3 +405,18 @@ func TestBatch(t *testing.T) {
 		require.Equal(t, exps, oris)
 	}
 }
+
+func TestShuffleWith(t *testing.T) {
+	for _, test := range []struct {
+		ori []any
+	}{
+		{[]any{}},
+		{[]any{1}},
+		{[]any{1, 2, 3, 4, 5, 6, 7, 8}},
+	} {
+		a := New(test.ori...).ShuffleWith(randx.NewPCG(1, 2))
+		b := New(test.ori...).ShuffleWith(randx.NewPCG(1, 2))
+		require.Equal(t, a, b)
+		require.ElementsMatch(t, test.ori, a.Finalize())
+	}
+}
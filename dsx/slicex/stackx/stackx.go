@@ -1,3 +1,4 @@
+// Package stackx implements a LIFO stack on a slice.
 package stackx
 
 import "github.com/unsafe-risk/utilx/dsx"
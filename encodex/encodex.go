@@ -1 +1,2 @@
+// Package encodex groups encoding helpers.
 package encodex
@@ -1 +1,2 @@
+// Package functionalx groups functional programming helpers.
 package functionalx
@@ -1,3 +1,4 @@
+// Package lazyx computes values once, on first use.
 package lazyx
 
 import "sync"
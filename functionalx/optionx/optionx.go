@@ -1,3 +1,4 @@
+// Package optionx implements an optional value.
 package optionx
 
 type Option[T any] struct {
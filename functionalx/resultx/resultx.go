@@ -1,3 +1,4 @@
+// Package resultx implements a value or an error.
 package resultx
 
 type Result[T any, E any] struct {
@@ -2,12 +2,17 @@ module github.com/unsafe-risk/utilx
 
 go 1.19
 
-require golang.org/x/crypto v0.4.0
+require (
+	github.com/BurntSushi/toml v1.5.0
+	golang.org/x/crypto v0.4.0
+	golang.org/x/mod v0.14.0
+	golang.org/x/text v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
@@ -1,3 +1,5 @@
+// Package decouplex decouples writers from a slow consumer with a lock-free
+// ring buffer.
 package decouplex
 
 import (
@@ -1 +1,2 @@
+// Package iox groups I/O helpers.
 package iox
@@ -1,3 +1,4 @@
+// Package closex closes a list of io.Closers at once and collects their errors.
 package closex
 
 import (
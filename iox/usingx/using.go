@@ -1,3 +1,4 @@
+// Package usingx runs a function and then closes resources.
 package usingx
 
 import (
@@ -1,6 +1,10 @@
+// Utilx is a collection of generic Go utilities, grouped by area into the
+// packages below. The main package only holds go:generate directives.
 package main
 
 //go:generate vstruct go internal ./cryptox/passhashx/internal/passhash.vstruct
+//go:generate go run ./cmd/hierarchy -o hierarchical.d2
+//go:generate go run ./cmd/hierarchy -format mermaid -o hierarchical.mmd
 
 func main() {
 
@@ -1,3 +1,4 @@
+// Package closex runs a handler when the process is asked to terminate.
 package closex
 
 import (
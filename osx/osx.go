@@ -1 +1,2 @@
+// Package osx groups operating system helpers.
 package osx
@@ -0,0 +1,77 @@
+package gopoolx
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/unsafe-risk/utilx/configx/optionalx"
+)
+
+// TimedPoolConfig holds the settings of a TimedPool.
+type TimedPoolConfig struct {
+	// MaxWorkers caps the number of running goroutines.
+	MaxWorkers int64
+	// IdleTimeout is how long a worker waits for a task before exiting.
+	IdleTimeout time.Duration
+	// GCPeriod is how often idle workers are looked for.
+	GCPeriod time.Duration
+	// Preheat is the number of workers started up front.
+	Preheat int
+}
+
+// DefaultTimedPoolConfig allows 256 workers per CPU that exit after 10
+// seconds of idleness.
+func DefaultTimedPoolConfig() TimedPoolConfig {
+	return TimedPoolConfig{
+		MaxWorkers:  256 * int64(runtime.GOMAXPROCS(0)),
+		IdleTimeout: 10 * time.Second,
+		GCPeriod:    time.Second,
+	}
+}
+
+func (c *TimedPoolConfig) Validate() error {
+	if c.Preheat < 0 || int64(c.Preheat) > c.MaxWorkers {
+		return ErrInvalidPreheat
+	}
+	return nil
+}
+
+type TimedPoolOption = optionalx.Option[TimedPoolConfig]
+
+func WithMaxWorkers(n int64) TimedPoolOption {
+	return func(c *TimedPoolConfig) error {
+		if n <= 0 {
+			return ErrInvalidMaxWorkers
+		}
+		c.MaxWorkers = n
+		return nil
+	}
+}
+
+func WithIdleTimeout(d time.Duration) TimedPoolOption {
+	return func(c *TimedPoolConfig) error {
+		if d <= 0 {
+			return ErrInvalidIdleTimeout
+		}
+		c.IdleTimeout = d
+		return nil
+	}
+}
+
+func WithGCPeriod(d time.Duration) TimedPoolOption {
+	return func(c *TimedPoolConfig) error {
+		if d <= 0 {
+			return ErrInvalidGCPeriod
+		}
+		c.GCPeriod = d
+		return nil
+	}
+}
+
+// WithPreheat starts n workers when the pool is created. n must not exceed
+// the maximum number of workers.
+func WithPreheat(n int) TimedPoolOption {
+	return optionalx.Func(func(c *TimedPoolConfig) {
+		c.Preheat = n
+	})
+}
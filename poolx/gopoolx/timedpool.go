@@ -1,5 +1,7 @@
 // TimedPool is a pool of goroutines with a idle timeout.
 
+// Package gopoolx runs tasks on a bounded pool of goroutines that exit when
+// idle.
 package gopoolx
 
 import (
@@ -7,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/unsafe-risk/utilx/configx/optionalx"
 	"github.com/unsafe-risk/utilx/syncx/syncpoolx"
 )
 
@@ -177,6 +180,7 @@ var (
 	ErrInvalidHandler     = errors.New("invalid handler, must not be nil")
 	ErrInvalidIdleTimeout = errors.New("invalid idle timeout, must be greater than 0s")
 	ErrInvalidGCPeriod    = errors.New("invalid gc period, must be greater than 0s")
+	ErrInvalidPreheat     = errors.New("invalid preheat, must be between 0 and max workers")
 )
 
 func NewTimedPool[T any](maxWorkers int64, handler func(T), idleTimeout, gcPeriod time.Duration, preheat int) (*TimedPool[T], error) {
@@ -193,15 +197,43 @@ func NewTimedPool[T any](maxWorkers int64, handler func(T), idleTimeout, gcPerio
 		return nil, ErrInvalidGCPeriod
 	}
 
+	return newTimedPool(handler, TimedPoolConfig{
+		MaxWorkers:  maxWorkers,
+		IdleTimeout: idleTimeout,
+		GCPeriod:    gcPeriod,
+		Preheat:     preheat,
+	}), nil
+}
+
+// NewTimedPoolWith is NewTimedPool configured with options applied over
+// DefaultTimedPoolConfig. Invalid options are reported together in an
+// *optionalx.Error.
+//
+//	pool, err := gopoolx.NewTimedPoolWith(handle,
+//		gopoolx.WithMaxWorkers(64),
+//		gopoolx.WithIdleTimeout(time.Minute),
+//	)
+func NewTimedPoolWith[T any](handler func(T), opts ...TimedPoolOption) (*TimedPool[T], error) {
+	if handler == nil {
+		return nil, ErrInvalidHandler
+	}
+	config, err := optionalx.New(DefaultTimedPoolConfig, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newTimedPool(handler, config), nil
+}
+
+func newTimedPool[T any](handler func(T), config TimedPoolConfig) *TimedPool[T] {
 	pool := &TimedPool[T]{
-		maxWorkers:  maxWorkers,
+		maxWorkers:  config.MaxWorkers,
 		handler:     handler,
-		idleTimeout: idleTimeout,
-		gcPeriod:    gcPeriod,
+		idleTimeout: config.IdleTimeout,
+		gcPeriod:    config.GCPeriod,
 	}
 
-	pool.start(preheat)
-	return pool, nil
+	pool.start(config.Preheat)
+	return pool
 }
 
 func (pool *TimedPool[T]) Stop() {
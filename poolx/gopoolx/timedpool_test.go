@@ -1,6 +1,7 @@
 package gopoolx_test
 
 import (
+	"errors"
 	"math"
 	"sync"
 	"sync/atomic"
@@ -208,3 +209,47 @@ func TestTimedPoolWorkerCount(t *testing.T) {
 		t.Errorf("workers = %d, want 0, goroutine leak!!!", w)
 	}
 }
+
+func TestNewTimedPoolWith(t *testing.T) {
+	var v uint64
+	pool, err := gopoolx.NewTimedPoolWith(
+		func(v *uint64) {
+			atomic.AddUint64(v, 1)
+		},
+		gopoolx.WithMaxWorkers(4),
+		gopoolx.WithIdleTimeout(time.Hour),
+		gopoolx.WithPreheat(4),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Stop()
+
+	if w := pool.Workers(); w != 4 {
+		t.Errorf("workers = %d, want 4", w)
+	}
+	for i := 0; i < 4; i++ {
+		if !pool.Run(&v) {
+			t.Errorf("run %d: pool is full", i)
+		}
+	}
+
+	_, err = gopoolx.NewTimedPoolWith(
+		func(int) {},
+		gopoolx.WithMaxWorkers(0),
+		gopoolx.WithGCPeriod(-time.Second),
+		gopoolx.WithPreheat(-1),
+	)
+	for _, want := range []error{gopoolx.ErrInvalidMaxWorkers, gopoolx.ErrInvalidGCPeriod, gopoolx.ErrInvalidPreheat} {
+		if !errors.Is(err, want) {
+			t.Errorf("err = %v, want it to include %v", err, want)
+		}
+	}
+	if errors.Is(err, gopoolx.ErrInvalidIdleTimeout) {
+		t.Errorf("err = %v, includes an option that was not given", err)
+	}
+
+	if _, err := gopoolx.NewTimedPoolWith[int](nil); err != gopoolx.ErrInvalidHandler {
+		t.Errorf("nil handler: err = %v, want %v", err, gopoolx.ErrInvalidHandler)
+	}
+}
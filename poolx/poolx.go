@@ -1 +1,2 @@
+// Package poolx groups pools.
 package poolx
@@ -1,3 +1,4 @@
+// Package lockx locks a pointer with compare-and-swap.
 package lockx
 
 import (
@@ -1,3 +1,4 @@
+// Package syncpoolx wraps sync.Pool with a type-safe API.
 package syncpoolx
 
 import "sync"
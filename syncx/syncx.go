@@ -1 +1,2 @@
+// Package syncx groups synchronization helpers.
 package syncx
@@ -0,0 +1,2 @@
+# Types of this package registered with convx by convx_gen.go.
+HexString string
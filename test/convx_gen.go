@@ -0,0 +1,41 @@
+// Code generated by convxgen. DO NOT EDIT.
+
+package main
+
+import "github.com/unsafe-risk/utilx/typex/convx"
+
+func init() {
+	convx.Register(convx.IntToString[int8, HexString])
+	convx.Register(convx.IntToString[int16, HexString])
+	convx.Register(convx.IntToString[int32, HexString])
+	convx.Register(convx.IntToString[int64, HexString])
+	convx.Register(convx.IntToString[int, HexString])
+	convx.Register(convx.UintToString[uint8, HexString])
+	convx.Register(convx.UintToString[uint16, HexString])
+	convx.Register(convx.UintToString[uint32, HexString])
+	convx.Register(convx.UintToString[uint64, HexString])
+	convx.Register(convx.UintToString[uint, HexString])
+	convx.Register(convx.FloatToString[float32, HexString])
+	convx.Register(convx.FloatToString[float64, HexString])
+	convx.Register(convx.StringToString[string, HexString])
+	convx.Register(convx.BoolToString[bool, HexString])
+	convx.Register(convx.BytesToString[HexString])
+	convx.Register(convx.RunesToString[HexString])
+	convx.Register(convx.StringToInt[HexString, int8])
+	convx.Register(convx.StringToInt[HexString, int16])
+	convx.Register(convx.StringToInt[HexString, int32])
+	convx.Register(convx.StringToInt[HexString, int64])
+	convx.Register(convx.StringToInt[HexString, int])
+	convx.Register(convx.StringToUint[HexString, uint8])
+	convx.Register(convx.StringToUint[HexString, uint16])
+	convx.Register(convx.StringToUint[HexString, uint32])
+	convx.Register(convx.StringToUint[HexString, uint64])
+	convx.Register(convx.StringToUint[HexString, uint])
+	convx.Register(convx.StringToFloat[HexString, float32])
+	convx.Register(convx.StringToFloat[HexString, float64])
+	convx.Register(convx.StringToString[HexString, string])
+	convx.Register(convx.StringToBool[HexString, bool])
+	convx.Register(convx.StringToBytes[HexString])
+	convx.Register(convx.StringToRunes[HexString])
+	convx.Register(convx.StringToString[HexString, HexString])
+}
@@ -1,5 +1,8 @@
+// Command test exercises typex/convx with a user type.
 package main
 
+//go:generate go run ../cmd/convxgen -spec convx.spec -o convx_gen.go
+
 import (
 	"encoding/hex"
 	"fmt"
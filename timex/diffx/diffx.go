@@ -1,3 +1,4 @@
+// Package diffx measures elapsed time between two points.
 package diffx
 
 import (
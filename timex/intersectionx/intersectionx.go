@@ -1,3 +1,4 @@
+// Package intersectionx finds the intersection of time intervals.
 package intersectionx
 
 import (
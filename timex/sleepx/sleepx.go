@@ -1,3 +1,4 @@
+// Package sleepx sleeps in a way that can be woken up early.
 package sleepx
 
 import (
@@ -1,3 +1,4 @@
+// Package timex returns times in UTC, and groups time helpers.
 package timex
 
 import "time"
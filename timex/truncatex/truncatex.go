@@ -1,3 +1,4 @@
+// Package truncatex truncates Unix times to the start of a calendar unit.
 package truncatex
 
 import (
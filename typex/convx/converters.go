@@ -6,96 +6,99 @@ import (
 	"golang.org/x/exp/constraints"
 )
 
-func integerToInteger[T, R constraints.Integer | constraints.Float](i T) R {
+// The converters below are generic over named types, so they can be
+// registered for user types too; see cmd/convxgen.
+
+func NumberToNumber[T, R constraints.Integer | constraints.Float](i T) R {
 	return R(i)
 }
 
-func intToString[T constraints.Signed](i T) string {
-	return strconv.FormatInt(int64(i), 10)
+func IntToString[T constraints.Signed, S ~string](i T) S {
+	return S(strconv.FormatInt(int64(i), 10))
 }
 
-func uintToString[T constraints.Unsigned](i T) string {
-	return strconv.FormatUint(uint64(i), 10)
+func UintToString[T constraints.Unsigned, S ~string](i T) S {
+	return S(strconv.FormatUint(uint64(i), 10))
 }
 
-func floatToString[T constraints.Float](i T) string {
-	return strconv.FormatFloat(float64(i), 'f', -1, 64)
+func FloatToString[T constraints.Float, S ~string](i T) S {
+	return S(strconv.FormatFloat(float64(i), 'f', -1, 64))
 }
 
-func stringToInteger[T constraints.Integer](s string) T {
-	i, _ := strconv.ParseInt(s, 10, 64)
+func StringToInt[S ~string, T constraints.Signed](s S) T {
+	i, _ := strconv.ParseInt(string(s), 10, 64)
 	return T(i)
 }
 
-func stringToUinteger[T constraints.Unsigned](s string) T {
-	i, _ := strconv.ParseUint(s, 10, 64)
+func StringToUint[S ~string, T constraints.Unsigned](s S) T {
+	i, _ := strconv.ParseUint(string(s), 10, 64)
 	return T(i)
 }
 
-func stringToFloat[T constraints.Float](s string) T {
-	i, _ := strconv.ParseFloat(s, 64)
+func StringToFloat[S ~string, T constraints.Float](s S) T {
+	i, _ := strconv.ParseFloat(string(s), 64)
 	return T(i)
 }
 
-func boolToString(b bool) string {
-	return strconv.FormatBool(b)
+func BoolToString[B ~bool, S ~string](b B) S {
+	return S(strconv.FormatBool(bool(b)))
 }
 
-func stringToBool(s string) bool {
-	b, _ := strconv.ParseBool(s)
-	return b
+func StringToBool[S ~string, B ~bool](s S) B {
+	b, _ := strconv.ParseBool(string(s))
+	return B(b)
 }
 
-func boolToInteger[T constraints.Integer](b bool) T {
+func BoolToInteger[B ~bool, T constraints.Integer](b B) T {
 	if b {
 		return T(1)
 	}
 	return T(0)
 }
 
-func intToBool[T constraints.Integer](i T) bool {
+func IntegerToBool[T constraints.Integer, B ~bool](i T) B {
 	return i != T(0)
 }
 
-func boolToFloat[T constraints.Float](b bool) T {
+func BoolToFloat[B ~bool, T constraints.Float](b B) T {
 	if b {
 		return T(1)
 	}
 	return T(0)
 }
 
-func floatToBool[T constraints.Float](f T) bool {
+func FloatToBool[T constraints.Float, B ~bool](f T) B {
 	return f != T(0)
 }
 
-func stringToString(s string) string {
-	return s
+func StringToString[S, R ~string](s S) R {
+	return R(s)
 }
 
-func boolToBool(b bool) bool {
-	return b
+func BoolToBool[B, R ~bool](b B) R {
+	return R(b)
 }
 
-func stringToBytes(s string) []byte {
+func StringToBytes[S ~string](s S) []byte {
 	return []byte(s)
 }
 
-func bytesToString(b []byte) string {
-	return string(b)
+func BytesToString[S ~string](b []byte) S {
+	return S(b)
 }
 
-func bytesToBytes(b []byte) []byte {
+func BytesToBytes(b []byte) []byte {
 	return b
 }
 
-func stringToRunes(s string) []rune {
+func StringToRunes[S ~string](s S) []rune {
 	return []rune(s)
 }
 
-func runesToString(r []rune) string {
-	return string(r)
+func RunesToString[S ~string](r []rune) S {
+	return S(r)
 }
 
-func runesToRunes(r []rune) []rune {
+func RunesToRunes(r []rune) []rune {
 	return r
 }
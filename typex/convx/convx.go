@@ -1,5 +1,9 @@
+// Package convx converts values between types through a registry of
+// converter functions.
 package convx
 
+//go:generate go run ../../cmd/convxgen -o init.go
+
 import (
 	"reflect"
 	"sync"
@@ -65,3 +69,18 @@ func IntoOr[T, R any](value T, or R) R {
 	}
 	return rs
 }
+
+// Convert is Into for types known only at run time: it converts value to a
+// value of type to with the registered converter, if there is one.
+func Convert(value any, to reflect.Type) (any, bool) {
+	if value == nil || to == nil {
+		return nil, false
+	}
+	convertersLock.RLock()
+	f := converters[reflect.TypeOf(value)][to]
+	convertersLock.RUnlock()
+	if f == nil {
+		return nil, false
+	}
+	return reflect.ValueOf(f).Call([]reflect.Value{reflect.ValueOf(value)})[0].Interface(), true
+}
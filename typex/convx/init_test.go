@@ -0,0 +1,90 @@
+package convx
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestRegistry checks the generated registrations against the conversions
+// convx is meant to support, derived independently from the type kinds.
+func TestRegistry(t *testing.T) {
+	types := []reflect.Type{reflect.TypeOf(""), reflect.TypeOf(false), reflect.TypeOf([]byte(nil)), reflect.TypeOf([]rune(nil))}
+	for _, v := range []any{int8(0), int16(0), int32(0), int64(0), 0, uint8(0), uint16(0), uint32(0), uint64(0), uint(0), float32(0), float64(0)} {
+		types = append(types, reflect.TypeOf(v))
+	}
+
+	scalar := func(t reflect.Type) bool {
+		return t.Kind() != reflect.Slice
+	}
+	want := func(from, to reflect.Type) bool {
+		switch {
+		case scalar(from) && scalar(to):
+			return true
+		case from.Kind() == reflect.String || to.Kind() == reflect.String:
+			// strings convert from and to bytes and runes
+			return true
+		default:
+			return from == to
+		}
+	}
+
+	convertersLock.RLock()
+	defer convertersLock.RUnlock()
+	var n int
+	for _, from := range types {
+		for _, to := range types {
+			c, ok := converters[from][to]
+			if ok != want(from, to) {
+				t.Errorf("%v to %v: registered = %v", from, to, ok)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			n++
+			if ct := reflect.TypeOf(c); ct != reflect.FuncOf([]reflect.Type{from}, []reflect.Type{to}, false) {
+				t.Errorf("%v to %v: converter has type %v", from, to, ct)
+			}
+		}
+	}
+	var total int
+	for _, m := range converters {
+		total += len(m)
+	}
+	if total != n {
+		t.Errorf("%d converters registered, want %d", total, n)
+	}
+}
+
+func TestInto(t *testing.T) {
+	if got := IntoOr(int64(-12), ""); got != "-12" {
+		t.Errorf("int64 to string: %q", got)
+	}
+	if got := IntoOr("200", uint8(0)); got != 200 {
+		t.Errorf("string to uint8: %v", got)
+	}
+	if got := IntoOr(float32(0.5), false); !got {
+		t.Errorf("float32 to bool: %v", got)
+	}
+	if got := IntoOr(true, float64(0)); got != 1 {
+		t.Errorf("bool to float64: %v", got)
+	}
+	if _, ok := Into[[]byte, int]([]byte("1")); ok {
+		t.Error("bytes to int converted")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	if got, ok := Convert("-7", reflect.TypeOf(int16(0))); !ok || got != int16(-7) {
+		t.Errorf("string to int16: %v, %v", got, ok)
+	}
+	if got, ok := Convert(3.0, reflect.TypeOf("")); !ok || got != "3" {
+		t.Errorf("float64 to string: %v, %v", got, ok)
+	}
+	if _, ok := Convert(struct{}{}, reflect.TypeOf("")); ok {
+		t.Error("struct to string converted")
+	}
+	if _, ok := Convert(nil, reflect.TypeOf("")); ok {
+		t.Error("nil converted")
+	}
+}
@@ -1 +1,2 @@
+// Package typex groups type helpers.
 package typex